@@ -0,0 +1,281 @@
+// Package bench plays many seeded games by strategies and compares their results
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/proxx/bot"
+	"github.com/proxx/game"
+)
+
+// z95 is z-score of 95% confidence intervals
+const z95 = 1.96
+
+// Contender is strategy taking part in the benchmark
+type Contender struct {
+	Name string
+	// New creates strategy for one worker, strategies are not shared between workers.
+	// strategy implementing io.Closer is closed when worker is done
+	New func(ctx context.Context, worker int) (bot.Strategy, error)
+}
+
+// Config describes the benchmark
+type Config struct {
+	Presets []game.Preset
+	// Games is number of games played by every contender on every preset
+	Games int
+	// Seed is seed of the first board, game i is played on board with seed Seed+i by every contender
+	Seed int64
+	// Workers is number of games played at the same time, number of CPUs by default
+	Workers int
+	// Progress is called after every game with number of played games and total number of games
+	Progress func(played, total int)
+}
+
+// Interval is estimate of value with 95% confidence interval
+type Interval struct {
+	Mean, Low, High float64
+}
+
+// Report is result of contender on preset
+type Report struct {
+	Contender string
+	Preset    string
+	Games     int
+	Won       int
+	// WinRate is share of won games with Wilson score interval
+	WinRate Interval
+	// Time is time of won games in seconds
+	Time Interval
+	// BBBVPerSecond is 3BV divided by time of won games
+	BBBVPerSecond Interval
+	// Failed is number of games lost since strategy failed, e.g. bot has exited
+	Failed int
+}
+
+// job is one game to play
+type job struct {
+	contender, preset int
+	seed              int64
+}
+
+// result is played game
+type result struct {
+	job
+	stats game.Stats
+	err   error
+}
+
+// Run plays Games games by every contender on every preset and reports results in order of contenders and presets.
+// failed strategy loses the game and is created again for the next one, error is returned only when strategy
+// can't be created or ctx is done
+func Run(ctx context.Context, cfg Config, contenders []Contender) ([]Report, error) {
+	if cfg.Games <= 0 {
+		return nil, errors.New("number of games must be positive")
+	}
+	if len(cfg.Presets) == 0 || len(contenders) == 0 {
+		return nil, errors.New("at least one preset and one strategy are needed")
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan job)
+	results := make(chan result)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			work(ctx, worker, contenders, cfg.Presets, jobs, results)
+		}(i)
+	}
+	go func() {
+		defer close(jobs)
+		for i := 0; i < cfg.Games; i++ {
+			for p := range cfg.Presets {
+				for c := range contenders {
+					select {
+					case jobs <- job{contender: c, preset: p, seed: cfg.Seed + int64(i)}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	total := cfg.Games * len(cfg.Presets) * len(contenders)
+	collected := make([][]result, len(contenders)*len(cfg.Presets))
+	var played int
+	var err error
+	for r := range results {
+		if errors.Is(r.err, errNotCreated) && err == nil {
+			err = r.err
+			cancel()
+		}
+		if err != nil {
+			continue
+		}
+		i := r.contender*len(cfg.Presets) + r.preset
+		collected[i] = append(collected[i], r)
+		played++
+		if cfg.Progress != nil {
+			cfg.Progress(played, total)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ctx.Err() != nil || played < total {
+		return nil, context.Canceled
+	}
+
+	reports := make([]Report, 0, len(collected))
+	for i, results := range collected {
+		c, p := contenders[i/len(cfg.Presets)], cfg.Presets[i%len(cfg.Presets)]
+		reports = append(reports, report(c.Name, p.Name, results))
+	}
+
+	return reports, nil
+}
+
+// errNotCreated wraps error of strategy creation, it stops the benchmark
+var errNotCreated = errors.New("strategy is not created")
+
+// work plays jobs by strategies created for the worker
+func work(ctx context.Context, worker int, contenders []Contender, presets []game.Preset, jobs <-chan job,
+	results chan<- result) {
+	strategies := make([]bot.Strategy, len(contenders))
+	// games is number of games played by every strategy, the protocol numbers games from 1
+	games := make([]int, len(contenders))
+	defer func() {
+		for _, s := range strategies {
+			closeStrategy(s)
+		}
+	}()
+
+	for j := range jobs {
+		r := result{job: j}
+		s := strategies[j.contender]
+		if s == nil {
+			var err error
+			s, err = contenders[j.contender].New(ctx, worker)
+			if err != nil {
+				r.err = fmt.Errorf("%w: %s: %v", errNotCreated, contenders[j.contender].Name, err)
+				results <- r
+				continue
+			}
+			strategies[j.contender] = s
+			games[j.contender] = 0
+		}
+		games[j.contender]++
+		r.stats, r.err = play(ctx, presets[j.preset], j.seed, s, games[j.contender])
+		if r.err != nil {
+			// failed strategy, e.g. bot which hasn't answered in time, can't be trusted with the next game
+			closeStrategy(s)
+			strategies[j.contender] = nil
+		}
+		results <- r
+	}
+}
+
+func play(ctx context.Context, p game.Preset, seed int64, s bot.Strategy, n int) (game.Stats, error) {
+	b, err := game.NewSeededBoard(p.Size, p.BlackHoles, seed)
+	if err != nil {
+		return game.Stats{}, err
+	}
+
+	return bot.Play(ctx, b, s, n)
+}
+
+func closeStrategy(s bot.Strategy) {
+	if c, ok := s.(io.Closer); ok {
+		_ = c.Close()
+	}
+}
+
+// report summarizes results of contender on preset
+func report(contender, preset string, results []result) Report {
+	r := Report{Contender: contender, Preset: preset, Games: len(results)}
+	var times, speeds []float64
+	for _, res := range results {
+		if res.err != nil {
+			r.Failed++
+		}
+		if !res.stats.Won {
+			continue
+		}
+		r.Won++
+		seconds := res.stats.Duration().Seconds()
+		times = append(times, seconds)
+		if seconds > 0 {
+			speeds = append(speeds, float64(res.stats.BBBV)/seconds)
+		}
+	}
+	r.WinRate = wilson(r.Won, r.Games)
+	r.Time = mean(times)
+	r.BBBVPerSecond = mean(speeds)
+
+	return r
+}
+
+// wilson returns share of successes with Wilson score interval, which is right even for shares close to 0 and 1
+func wilson(successes, n int) Interval {
+	if n == 0 {
+		return Interval{}
+	}
+	p := float64(successes) / float64(n)
+	z2 := z95 * z95
+	nf := float64(n)
+	center := (p + z2/(2*nf)) / (1 + z2/nf)
+	margin := z95 * math.Sqrt(p*(1-p)/nf+z2/(4*nf*nf)) / (1 + z2/nf)
+
+	return Interval{Mean: p, Low: math.Max(0, center-margin), High: math.Min(1, center+margin)}
+}
+
+// mean returns mean of values with normal confidence interval
+func mean(values []float64) Interval {
+	if len(values) == 0 {
+		return Interval{}
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	n := float64(len(values))
+	m := sum / n
+	if len(values) == 1 {
+		return Interval{Mean: m, Low: m, High: m}
+	}
+	var squares float64
+	for _, v := range values {
+		squares += (v - m) * (v - m)
+	}
+	margin := z95 * math.Sqrt(squares/(n-1)/n)
+
+	return Interval{Mean: m, Low: m - margin, High: m + margin}
+}
+
+// Duration converts interval of seconds to durations
+func (i Interval) Duration() (mean, low, high time.Duration) {
+	seconds := func(v float64) time.Duration {
+		return time.Duration(v * float64(time.Second))
+	}
+
+	return seconds(i.Mean), seconds(i.Low), seconds(i.High)
+}
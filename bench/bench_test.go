@@ -0,0 +1,160 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/proxx/bot"
+	"github.com/proxx/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// journal collects what recorders of one contender have seen
+type journal struct {
+	mu        sync.Mutex
+	recorders []*recorder
+	// boards are results of finished games, the same boards played the same way give the same results
+	boards []string
+}
+
+// recorder opens cells in order and records numbers of games it was started on
+type recorder struct {
+	journal *journal
+	games   []int
+	failAt  int
+}
+
+func (r *recorder) StartGame(_ context.Context, info bot.GameInfo) error {
+	r.games = append(r.games, info.Game)
+	return nil
+}
+
+func (r *recorder) FinishGame(_ context.Context, _ bot.GameInfo, stats game.Stats) error {
+	r.journal.mu.Lock()
+	defer r.journal.mu.Unlock()
+	r.journal.boards = append(r.journal.boards, fmt.Sprintf("won %v, moves %d, 3BV %d, cleared %d",
+		stats.Won, stats.Moves, stats.BBBV, stats.Cleared))
+
+	return nil
+}
+
+func (r *recorder) Next(_ context.Context, v game.View) (bot.Action, error) {
+	snapshot := v.Snapshot()
+	for _, row := range snapshot.Cells {
+		for _, c := range row {
+			if c.State != game.CellClosed {
+				continue
+			}
+			if c.Row == r.failAt {
+				return bot.Action{}, errors.New("bot has exited")
+			}
+			return bot.Action{Kind: bot.Open, Row: c.Row, Col: c.Col}, nil
+		}
+	}
+
+	return bot.Action{Kind: bot.Resign}, nil
+}
+
+func contender(name string, failAt int, j *journal) Contender {
+	return Contender{Name: name, New: func(context.Context, int) (bot.Strategy, error) {
+		r := &recorder{journal: j, failAt: failAt}
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		j.recorders = append(j.recorders, r)
+		return r, nil
+	}}
+}
+
+func TestRun(t *testing.T) {
+	var first, second, failing journal
+	presets := []game.Preset{{Name: "tiny", Size: 3, BlackHoles: 1}, {Name: "empty", Size: 3}}
+	reports, err := Run(context.Background(), Config{Presets: presets, Games: 20, Seed: 100, Workers: 4},
+		[]Contender{contender("first", -1, &first), contender("second", -1, &second), contender("failing", 0, &failing)})
+	require.NoError(t, err)
+	require.Len(t, reports, 6)
+
+	// contenders playing the same way get the same results, since they play the same boards
+	sort.Strings(first.boards)
+	sort.Strings(second.boards)
+	assert.Len(t, first.boards, 40)
+	assert.Equal(t, first.boards, second.boards)
+
+	// every strategy numbers its games from 1, failed strategy is created again
+	for _, j := range []*journal{&first, &second, &failing} {
+		var games int
+		for _, r := range j.recorders {
+			for i, n := range r.games {
+				assert.Equal(t, i+1, n)
+			}
+			games += len(r.games)
+		}
+		assert.Equal(t, 40, games)
+	}
+	assert.Len(t, failing.recorders, 40)
+
+	for _, r := range reports {
+		assert.Equal(t, 20, r.Games)
+	}
+	assert.Equal(t, "first", reports[1].Contender)
+	assert.Equal(t, "empty", reports[1].Preset)
+	assert.Equal(t, 20, reports[1].Won)
+	assert.Equal(t, 1.0, reports[1].WinRate.Mean)
+	assert.Zero(t, reports[1].Failed)
+	assert.Equal(t, "failing", reports[5].Contender)
+	assert.Equal(t, 20, reports[5].Failed)
+	assert.Zero(t, reports[5].Won)
+}
+
+func TestRun_Errors(t *testing.T) {
+	presets := []game.Preset{{Name: "tiny", Size: 3, BlackHoles: 1}}
+	broken := Contender{Name: "broken", New: func(context.Context, int) (bot.Strategy, error) {
+		return nil, errors.New("command not found")
+	}}
+
+	_, err := Run(context.Background(), Config{Presets: presets, Games: 10}, []Contender{broken})
+	assert.EqualError(t, err, "strategy is not created: broken: command not found")
+
+	_, err = Run(context.Background(), Config{Presets: presets}, []Contender{broken})
+	assert.EqualError(t, err, "number of games must be positive")
+
+	_, err = Run(context.Background(), Config{Games: 10}, []Contender{broken})
+	assert.EqualError(t, err, "at least one preset and one strategy are needed")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var first journal
+	_, err = Run(ctx, Config{Presets: presets, Games: 10}, []Contender{contender("first", -1, &first)})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestWilson(t *testing.T) {
+	tests := []struct {
+		successes, n    int
+		wantLow, wantHi float64
+	}{
+		{successes: 0, n: 0},
+		{successes: 0, n: 100, wantLow: 0, wantHi: 0.037},
+		{successes: 50, n: 100, wantLow: 0.404, wantHi: 0.596},
+		{successes: 100, n: 100, wantLow: 0.963, wantHi: 1},
+	}
+	for _, tt := range tests {
+		got := wilson(tt.successes, tt.n)
+		assert.InDelta(t, tt.wantLow, got.Low, 0.001)
+		assert.InDelta(t, tt.wantHi, got.High, 0.001)
+	}
+}
+
+func TestMean(t *testing.T) {
+	assert.Equal(t, Interval{}, mean(nil))
+	assert.Equal(t, Interval{Mean: 2, Low: 2, High: 2}, mean([]float64{2}))
+
+	got := mean([]float64{1, 2, 3, 4})
+	assert.InDelta(t, 2.5, got.Mean, 1e-9)
+	assert.InDelta(t, 2.5-1.96*0.6455, got.Low, 0.001)
+	assert.InDelta(t, 2.5+1.96*0.6455, got.High, 0.001)
+}
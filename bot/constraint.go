@@ -0,0 +1,72 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+
+	"github.com/proxx/game"
+)
+
+// errNoClosedCells is returned by strategies asked to move when every cell is opened or flagged
+var errNoClosedCells = errors.New("there are no closed cells")
+
+// Constraint finds every placement of black holes matching numbers of opened cells and the number of remaining
+// black holes, and opens cells which are safe in all of them. random cell without certain black hole is opened
+// when there is no safe cell. it never flags
+type Constraint struct {
+	rand *rand.Rand
+	// safe are cells found safe by the last analysis, they stay safe till the end of the game
+	safe []game.CellView
+}
+
+// NewConstraint creates constraint solver strategy, seed is used by random guesses
+func NewConstraint(seed int64) *Constraint {
+	// excluding this from linter check since strategy doesn't need secure random numbers
+	//nolint: gosec
+	return &Constraint{rand: rand.New(rand.NewSource(seed))}
+}
+
+// StartGame forgets safe cells of the previous game
+func (s *Constraint) StartGame(context.Context, GameInfo) error {
+	s.safe = nil
+	return nil
+}
+
+// Next opens safe cell or guesses
+func (s *Constraint) Next(_ context.Context, v game.View) (Action, error) {
+	if a, ok := nextSafe(v, &s.safe); ok {
+		return a, nil
+	}
+
+	a := analyse(v.Snapshot())
+	if s.safe = a.safe(); len(s.safe) > 0 {
+		next, _ := nextSafe(v, &s.safe)
+		return next, nil
+	}
+	var guesses []game.CellView
+	for i, p := range a.probability {
+		if p < 1 {
+			guesses = append(guesses, a.cells[i])
+		}
+	}
+	if len(guesses) == 0 {
+		return Action{}, errNoClosedCells
+	}
+	c := guesses[s.rand.Intn(len(guesses))]
+
+	return Action{Kind: Open, Row: c.Row, Col: c.Col}, nil
+}
+
+// nextSafe pops the first of safe cells which is still closed and opens it
+func nextSafe(v game.View, safe *[]game.CellView) (Action, bool) {
+	for len(*safe) > 0 {
+		c := (*safe)[0]
+		*safe = (*safe)[1:]
+		if current, err := v.Cell(c.Row, c.Col); err == nil && current.State == game.CellClosed {
+			return Action{Kind: Open, Row: c.Row, Col: c.Col}, true
+		}
+	}
+
+	return Action{}, false
+}
@@ -0,0 +1,36 @@
+// Package bot plays games by strategies: Go ones or external programs speaking the bot protocol.
+//
+// Strategy gets read-only view of the board and returns action, Play runs it on any Playground. Reference
+// strategies, created by name with NewStrategy: random opens random closed cell, simple applies single cell
+// rule, constraint opens cells safe in every placement of black holes matching the numbers and probability
+// guesses the cell with the lowest probability of black hole when there is no safe one.
+//
+// Bot protocol, version 1. Engine starts the bot as a subprocess and talks to it over stdin and stdout
+// with JSON objects, one per line. Whatever bot writes to stderr is passed through, so it could be used for logs.
+//
+// Engine sends to bot:
+//
+//	{"type":"hello","version":1}
+//		once, when bot is started. bot answers with hello of the version it speaks
+//	{"type":"game","game":1,"rows":9,"cols":9,"blackHoles":10}
+//		new game is started, games are numbered from 1
+//	{"type":"state","game":1,"move":0,"remainingHoles":10,"flags":0,"cells":["#########", ...]}
+//		board before the move, bot answers with action. cells are rows of symbols:
+//		# closed, F flagged, 0-8 opened with number of black holes around, * black hole
+//	{"type":"error","error":"cell already opened"}
+//		the last action is rejected, state follows and bot answers with another action
+//	{"type":"result","game":1,"won":true,"moves":25,"cleared":71,"durationMs":130,"bbbv":17}
+//		game is over
+//	{"type":"bye"}
+//		all games are played, stdin is closed after it and bot should exit
+//
+// Bot sends to engine:
+//
+//	{"type":"hello","version":1,"name":"my-bot"}
+//		answer to hello of engine
+//	{"action":"open","row":0,"col":2}
+//		answer to state. actions are open, flag, chord and resign, coordinates start from zero
+//
+// Bot that doesn't answer in time, exits or sends malformed line loses the game and is stopped.
+// Bot that makes too many rejected actions in a row resigns the game.
+package bot
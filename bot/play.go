@@ -0,0 +1,145 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/proxx/game"
+)
+
+const (
+	// MaxRejected is number of rejected actions in a row after which the game is resigned
+	MaxRejected = 10
+	// movesPerCell limits number of moves in the game, so strategy toggling flags forever is stopped
+	movesPerCell = 3
+)
+
+// ActionKind is kind of action made by strategy
+type ActionKind string
+
+// list of action kinds
+const (
+	Open   ActionKind = "open"
+	Flag   ActionKind = "flag"
+	Chord  ActionKind = "chord"
+	Resign ActionKind = "resign"
+)
+
+// Action is move chosen by strategy. Row and Col are not used by Resign
+type Action struct {
+	Kind ActionKind `json:"action"`
+	Row  int        `json:"row"`
+	Col  int        `json:"col"`
+}
+
+func (a Action) String() string {
+	if a.Kind == Resign {
+		return string(a.Kind)
+	}
+
+	return fmt.Sprintf("%s %d %d", a.Kind, a.Row, a.Col)
+}
+
+// Strategy chooses moves by what is visible on the board
+type Strategy interface {
+	Next(ctx context.Context, v game.View) (Action, error)
+}
+
+// GameInfo describes game strategy is going to play
+type GameInfo struct {
+	// Game is number of the game played by strategy, starting from 1
+	Game       int
+	Rows, Cols int
+	BlackHoles int
+}
+
+// starter is implemented by strategies which prepare for the new game
+type starter interface {
+	StartGame(ctx context.Context, info GameInfo) error
+}
+
+// rejecter is implemented by strategies which are told about rejected actions
+type rejecter interface {
+	Rejected(ctx context.Context, a Action, err error) error
+}
+
+// finisher is implemented by strategies which are told about results of games
+type finisher interface {
+	FinishGame(ctx context.Context, info GameInfo, stats game.Stats) error
+}
+
+// Play plays the game on playground by strategy until it is won or lost. game is resigned when strategy fails,
+// makes MaxRejected rejected actions in a row or too many moves; strategy error is returned with the lost game.
+// n is number of the game reported to strategy
+func Play(ctx context.Context, pg game.Playground, s Strategy, n int) (game.Stats, error) {
+	rows, cols := pg.Dimensions()
+	if rows == 0 {
+		return game.Stats{}, errors.New("infinite board can't be played by bot, it is never won")
+	}
+	g := game.NewGame(pg)
+	// nothing is flagged before the game, so all black holes remain
+	info := GameInfo{Game: n, Rows: rows, Cols: cols, BlackHoles: pg.RemainingHoles()}
+	if st, ok := s.(starter); ok {
+		err := st.StartGame(ctx, info)
+		if err != nil {
+			_ = g.Resign()
+			return g.Stats(), err
+		}
+	}
+
+	err := play(ctx, g, pg, s, movesPerCell*rows*cols)
+	if err != nil {
+		_ = g.Resign()
+		return g.Stats(), err
+	}
+	stats := g.Stats()
+	if f, ok := s.(finisher); ok {
+		err = f.FinishGame(ctx, info, stats)
+	}
+
+	return stats, err
+}
+
+func play(ctx context.Context, g *game.Game, v game.View, s Strategy, maxMoves int) error {
+	var rejected int
+	for moves := 0; !g.IsFinished(); moves++ {
+		if moves >= maxMoves || rejected >= MaxRejected {
+			return g.Resign()
+		}
+		a, err := s.Next(ctx, v)
+		if err != nil {
+			return err
+		}
+		err = apply(g, a)
+		if err == nil {
+			rejected = 0
+			continue
+		}
+		rejected++
+		if r, ok := s.(rejecter); ok {
+			err = r.Rejected(ctx, a, err)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// apply makes action in the game
+func apply(g *game.Game, a Action) error {
+	switch a.Kind {
+	case Open:
+		return g.Open(a.Row, a.Col)
+	case Flag:
+		return g.Flag(a.Row, a.Col)
+	case Chord:
+		return g.Chord(a.Row, a.Col)
+	case Resign:
+		return g.Resign()
+	default:
+		return fmt.Errorf("unknown action %q", a.Kind)
+	}
+}
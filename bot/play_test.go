@@ -0,0 +1,132 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/proxx/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// script is strategy making given actions, the last action is repeated when script is over
+type script struct {
+	actions  []Action
+	err      error
+	started  []GameInfo
+	rejected []string
+	finished []bool
+}
+
+func (s *script) Next(context.Context, game.View) (Action, error) {
+	if s.err != nil && len(s.actions) == 0 {
+		return Action{}, s.err
+	}
+	a := s.actions[0]
+	if len(s.actions) > 1 || s.err != nil {
+		s.actions = s.actions[1:]
+	}
+
+	return a, nil
+}
+
+func (s *script) StartGame(_ context.Context, info GameInfo) error {
+	s.started = append(s.started, info)
+	return nil
+}
+
+func (s *script) Rejected(_ context.Context, a Action, err error) error {
+	s.rejected = append(s.rejected, a.String()+": "+err.Error())
+	return nil
+}
+
+func (s *script) FinishGame(_ context.Context, _ GameInfo, stats game.Stats) error {
+	s.finished = append(s.finished, stats.Won)
+	return nil
+}
+
+// newTestBoard creates 3x3 board with black hole at [0 1]
+func newTestBoard(t *testing.T) *game.Board {
+	b, err := game.NewBoardFromLayout(game.Layout{Rows: 3, Cols: 3, BlackHoles: [][]int{{0, 1}}})
+	require.NoError(t, err)
+
+	return b
+}
+
+func TestPlay(t *testing.T) {
+	tests := []struct {
+		name         string
+		script       *script
+		wantWon      bool
+		wantMoves    int
+		wantRejected []string
+		wantErr      string
+	}{
+		{
+			name: "won",
+			script: &script{actions: []Action{
+				{Kind: Open, Row: 2, Col: 2}, {Kind: Flag, Row: 0, Col: 1}, {Kind: Chord, Row: 1, Col: 1},
+			}},
+			wantWon:   true,
+			wantMoves: 3,
+		},
+		{
+			name: "rejected",
+			script: &script{actions: []Action{
+				{Kind: Open, Row: 2, Col: 2}, {Kind: Open, Row: 2, Col: 2}, {Kind: "jump"}, {Kind: Open, Row: 0, Col: 1},
+			}},
+			wantMoves:    2,
+			wantRejected: []string{"open 2 2: cell already opened", `jump 0 0: unknown action "jump"`},
+		},
+		{
+			name:      "resigned",
+			script:    &script{actions: []Action{{Kind: Open, Row: 2, Col: 2}, {Kind: Resign}}},
+			wantMoves: 1,
+		},
+		{
+			name:         "too_many_rejected",
+			script:       &script{actions: []Action{{Kind: Open, Row: 5, Col: 5}}},
+			wantRejected: repeat("open 5 5: "+(&game.OutOfBoundsError{Row: 5, Col: 5, Rows: 3, Cols: 3}).Error(), MaxRejected),
+		},
+		{
+			name:      "strategy_failed",
+			script:    &script{actions: []Action{{Kind: Open, Row: 2, Col: 2}}, err: errors.New("bot has exited")},
+			wantMoves: 1,
+			wantErr:   "bot has exited",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := Play(context.Background(), newTestBoard(t), tt.script, 1)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Empty(t, tt.script.finished)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, []bool{tt.wantWon}, tt.script.finished)
+			}
+			assert.Equal(t, tt.wantWon, stats.Won)
+			assert.Equal(t, tt.wantMoves, stats.Moves)
+			assert.Equal(t, []GameInfo{{Game: 1, Rows: 3, Cols: 3, BlackHoles: 1}}, tt.script.started)
+			assert.Equal(t, tt.wantRejected, tt.script.rejected)
+		})
+	}
+}
+
+func repeat(s string, n int) []string {
+	repeated := make([]string, n)
+	for i := range repeated {
+		repeated[i] = s
+	}
+
+	return repeated
+}
+
+func TestPlay_Infinite(t *testing.T) {
+	b, err := game.NewInfiniteBoard(1, 0.15)
+	require.NoError(t, err)
+
+	_, err = Play(context.Background(), b, &script{}, 1)
+	assert.EqualError(t, err, "infinite board can't be played by bot, it is never won")
+}
@@ -0,0 +1,49 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/proxx/game"
+)
+
+// Probability solves the board like Constraint, but when there is no safe cell it opens the cell
+// with the lowest probability of black hole. ties are broken by order of cells, row by row
+type Probability struct {
+	safe []game.CellView
+}
+
+// NewProbability creates probability minimising strategy, it makes no random choices
+func NewProbability() *Probability {
+	return &Probability{}
+}
+
+// StartGame forgets safe cells of the previous game
+func (s *Probability) StartGame(context.Context, GameInfo) error {
+	s.safe = nil
+	return nil
+}
+
+// Next opens safe cell or the least dangerous one
+func (s *Probability) Next(_ context.Context, v game.View) (Action, error) {
+	if a, ok := nextSafe(v, &s.safe); ok {
+		return a, nil
+	}
+
+	a := analyse(v.Snapshot())
+	if s.safe = a.safe(); len(s.safe) > 0 {
+		next, _ := nextSafe(v, &s.safe)
+		return next, nil
+	}
+	best := -1
+	for i, p := range a.probability {
+		if best < 0 || p < a.probability[best] {
+			best = i
+		}
+	}
+	if best < 0 {
+		return Action{}, errNoClosedCells
+	}
+	c := a.cells[best]
+
+	return Action{Kind: Open, Row: c.Row, Col: c.Col}, nil
+}
@@ -0,0 +1,291 @@
+package bot
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/proxx/game"
+)
+
+const (
+	// ProtocolVersion is version of the bot protocol spoken by engine
+	ProtocolVersion = 1
+	// DefaultMoveTimeout is time bot has to answer
+	DefaultMoveTimeout = 5 * time.Second
+	// maxLineSize limits size of line sent by bot
+	maxLineSize = 1 << 16
+	// exitTimeout is time bot has to exit after bye
+	exitTimeout = 2 * time.Second
+)
+
+// list of messages sent to bot
+const (
+	messageHello  = "hello"
+	messageGame   = "game"
+	messageState  = "state"
+	messageError  = "error"
+	messageResult = "result"
+	messageBye    = "bye"
+)
+
+// ErrBotExited is returned when bot closes its output
+var ErrBotExited = errors.New("bot has exited")
+
+// message is line sent to bot, fields not used by the type are omitted
+type message struct {
+	Type           string   `json:"type"`
+	Version        int      `json:"version,omitempty"`
+	Game           int      `json:"game,omitempty"`
+	Rows           int      `json:"rows,omitempty"`
+	Cols           int      `json:"cols,omitempty"`
+	BlackHoles     int      `json:"blackHoles,omitempty"`
+	Move           *int     `json:"move,omitempty"`
+	RemainingHoles *int     `json:"remainingHoles,omitempty"`
+	Flags          *int     `json:"flags,omitempty"`
+	Cells          []string `json:"cells,omitempty"`
+	Error          string   `json:"error,omitempty"`
+	Won            *bool    `json:"won,omitempty"`
+	Moves          *int     `json:"moves,omitempty"`
+	Cleared        *int     `json:"cleared,omitempty"`
+	DurationMs     *int64   `json:"durationMs,omitempty"`
+	BBBV           *int     `json:"bbbv,omitempty"`
+}
+
+// reply is line sent by bot: hello or action
+type reply struct {
+	Type    string `json:"type"`
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+	Action
+}
+
+// line is line read from bot
+type line struct {
+	data []byte
+	err  error
+}
+
+// Process is external bot speaking the bot protocol. it is a Strategy, so it is played by Play
+type Process struct {
+	cmd         *exec.Cmd
+	stdin       io.WriteCloser
+	lines       chan line
+	moveTimeout time.Duration
+	// name is name bot introduced itself with
+	name  string
+	game  int
+	moves int
+}
+
+// ProcessOption configures the bot process
+type ProcessOption func(p *Process)
+
+// WithMoveTimeout sets time bot has to answer
+func WithMoveTimeout(d time.Duration) ProcessOption {
+	return func(p *Process) {
+		p.moveTimeout = d
+	}
+}
+
+// Start starts bot command and greets it. bot is stopped when ctx is done
+func Start(ctx context.Context, command []string, opts ...ProcessOption) (*Process, error) {
+	if len(command) == 0 {
+		return nil, errors.New("bot command is empty")
+	}
+	//nolint: gosec // running bot chosen by user is the purpose of the command
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	p := &Process{cmd: cmd, stdin: stdin, lines: make(chan line), moveTimeout: DefaultMoveTimeout}
+	for _, opt := range opts {
+		opt(p)
+	}
+	err = cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+	go p.read(stdout)
+
+	err = p.hello(ctx)
+	if err != nil {
+		_ = p.cmd.Process.Kill()
+		_ = p.wait()
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// read passes lines of bot output to lines channel until output is closed
+func (p *Process) read(stdout io.Reader) {
+	defer close(p.lines)
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 1024), maxLineSize)
+	for scanner.Scan() {
+		data := append([]byte(nil), scanner.Bytes()...)
+		p.lines <- line{data: data}
+	}
+	if err := scanner.Err(); err != nil {
+		p.lines <- line{err: err}
+	}
+}
+
+func (p *Process) hello(ctx context.Context) error {
+	err := p.send(message{Type: messageHello, Version: ProtocolVersion})
+	if err != nil {
+		return err
+	}
+	r, err := p.receive(ctx)
+	if err != nil {
+		return err
+	}
+	if r.Type != messageHello {
+		return fmt.Errorf("bot answered %q to hello", r.Type)
+	}
+	if r.Version != ProtocolVersion {
+		return fmt.Errorf("bot speaks protocol version %d, expected %d", r.Version, ProtocolVersion)
+	}
+	p.name = r.Name
+
+	return nil
+}
+
+// Name returns name bot introduced itself with
+func (p *Process) Name() string {
+	return p.name
+}
+
+// StartGame tells bot about new game
+func (p *Process) StartGame(_ context.Context, info GameInfo) error {
+	p.game = info.Game
+	p.moves = 0
+
+	return p.send(message{
+		Type:       messageGame,
+		Game:       info.Game,
+		Rows:       info.Rows,
+		Cols:       info.Cols,
+		BlackHoles: info.BlackHoles,
+	})
+}
+
+// Next sends visible board to bot and waits for its action
+func (p *Process) Next(ctx context.Context, v game.View) (Action, error) {
+	snapshot := v.Snapshot()
+	move := p.moves
+	p.moves++
+	err := p.send(message{
+		Type:           messageState,
+		Game:           p.game,
+		Move:           &move,
+		RemainingHoles: &snapshot.RemainingHoles,
+		Flags:          &snapshot.Flags,
+		Cells:          snapshot.Symbols(),
+	})
+	if err != nil {
+		return Action{}, err
+	}
+	r, err := p.receive(ctx)
+	if err != nil {
+		return Action{}, err
+	}
+
+	return r.Action, nil
+}
+
+// Rejected tells bot why its action is rejected
+func (p *Process) Rejected(_ context.Context, _ Action, err error) error {
+	return p.send(message{Type: messageError, Error: err.Error()})
+}
+
+// FinishGame tells bot result of the game
+func (p *Process) FinishGame(_ context.Context, info GameInfo, stats game.Stats) error {
+	duration := stats.Duration().Milliseconds()
+
+	return p.send(message{
+		Type:       messageResult,
+		Game:       info.Game,
+		Won:        &stats.Won,
+		Moves:      &stats.Moves,
+		Cleared:    &stats.Cleared,
+		DurationMs: &duration,
+		BBBV:       &stats.BBBV,
+	})
+}
+
+// Close says bye to bot and waits for it to exit, bot that doesn't exit in time is killed
+func (p *Process) Close() error {
+	_ = p.send(message{Type: messageBye})
+	_ = p.stdin.Close()
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- p.wait()
+	}()
+	select {
+	case err := <-exited:
+		return err
+	case <-time.After(exitTimeout):
+		_ = p.cmd.Process.Kill()
+		return <-exited
+	}
+}
+
+// wait waits for bot to exit. output is drained, so bot writing after bye isn't blocked
+func (p *Process) wait() error {
+	for range p.lines {
+	}
+
+	return p.cmd.Wait()
+}
+
+func (p *Process) send(m message) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = p.stdin.Write(append(data, '\n'))
+
+	return err
+}
+
+// receive waits for the next line of bot
+func (p *Process) receive(ctx context.Context) (reply, error) {
+	timer := time.NewTimer(p.moveTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return reply{}, ctx.Err()
+	case <-timer.C:
+		return reply{}, fmt.Errorf("bot hasn't answered in %v", p.moveTimeout)
+	case l, ok := <-p.lines:
+		if !ok {
+			return reply{}, ErrBotExited
+		}
+		if l.err != nil {
+			return reply{}, fmt.Errorf("reading bot output: %w", l.err)
+		}
+		var r reply
+		err := json.Unmarshal(l.data, &r)
+		if err != nil {
+			return reply{}, fmt.Errorf("bot sent malformed line %q: %w", l.data, err)
+		}
+
+		return r, nil
+	}
+}
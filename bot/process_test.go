@@ -0,0 +1,124 @@
+package bot
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/proxx/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBotMode makes test binary run as a bot, the value is behavior of the bot
+const testBotMode = "PROXX_TEST_BOT"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(testBotMode); mode != "" {
+		os.Exit(runTestBot(mode))
+	}
+	os.Exit(m.Run())
+}
+
+// runTestBot speaks the bot protocol: it sends invalid action first in every game and then opens closed cells
+// from the bottom right corner. exit code tells whether engine followed the protocol
+func runTestBot(mode string) int {
+	out := json.NewEncoder(os.Stdout)
+	in := bufio.NewScanner(os.Stdin)
+	in.Buffer(nil, maxLineSize)
+	var rejected, results int
+	for in.Scan() {
+		var m message
+		if json.Unmarshal(in.Bytes(), &m) != nil {
+			return 2
+		}
+		switch {
+		case mode == "silent":
+		case mode == "garbage":
+			fmt.Println("open 1 1")
+		case m.Type == messageHello && mode == "old":
+			_ = out.Encode(reply{Type: messageHello, Version: ProtocolVersion + 1})
+		case m.Type == messageHello:
+			_ = out.Encode(reply{Type: messageHello, Version: ProtocolVersion, Name: "corner"})
+		case m.Type == messageState && *m.Move == 0:
+			_ = out.Encode(Action{Kind: "jump"})
+		case m.Type == messageState:
+			_ = out.Encode(lastClosed(m.Cells))
+		case m.Type == messageError:
+			rejected++
+		case m.Type == messageResult:
+			results++
+		case m.Type == messageBye:
+			// every game starts with rejected action
+			if rejected != results {
+				return 3
+			}
+			return 0
+		}
+	}
+
+	return 4
+}
+
+func lastClosed(cells []string) Action {
+	for row := len(cells) - 1; row >= 0; row-- {
+		if col := strings.LastIndexByte(cells[row], '#'); col >= 0 {
+			return Action{Kind: Open, Row: row, Col: col}
+		}
+	}
+
+	return Action{Kind: Resign}
+}
+
+func startTestBot(t *testing.T, mode string) (*Process, error) {
+	t.Setenv(testBotMode, mode)
+
+	return Start(context.Background(), []string{os.Args[0]}, WithMoveTimeout(time.Second))
+}
+
+func TestProcess(t *testing.T) {
+	p, err := startTestBot(t, "corner")
+	require.NoError(t, err)
+	assert.Equal(t, "corner", p.Name())
+
+	// the first click clears board with black hole in the corner
+	b, err := game.NewBoardFromLayout(game.Layout{Rows: 3, Cols: 3, BlackHoles: [][]int{{0, 0}}})
+	require.NoError(t, err)
+	stats, err := Play(context.Background(), b, p, 1)
+	require.NoError(t, err)
+	assert.True(t, stats.Won)
+	assert.Equal(t, 1, stats.Moves)
+
+	b, err = game.NewBoardFromLayout(game.Layout{Rows: 3, Cols: 3, BlackHoles: [][]int{{2, 2}}})
+	require.NoError(t, err)
+	stats, err = Play(context.Background(), b, p, 2)
+	require.NoError(t, err)
+	assert.False(t, stats.Won)
+
+	assert.NoError(t, p.Close())
+}
+
+func TestProcess_Errors(t *testing.T) {
+	tests := []struct {
+		mode    string
+		wantErr string
+	}{
+		{mode: "old", wantErr: "bot speaks protocol version 2, expected 1"},
+		{mode: "silent", wantErr: "bot hasn't answered in 1s"},
+		{mode: "garbage", wantErr: `bot sent malformed line "open 1 1": invalid character 'o' looking for beginning of value`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			_, err := startTestBot(t, tt.mode)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+
+	_, err := Start(context.Background(), nil)
+	assert.EqualError(t, err, "bot command is empty")
+}
@@ -0,0 +1,39 @@
+package bot
+
+import (
+	"context"
+	"math/rand"
+
+	"github.com/proxx/game"
+)
+
+// Random opens random closed cell. it is the baseline other strategies are compared with
+type Random struct {
+	rand *rand.Rand
+}
+
+// NewRandom creates random strategy, the same seed makes the same choices on the same boards
+func NewRandom(seed int64) *Random {
+	// excluding this from linter check since strategy doesn't need secure random numbers
+	//nolint: gosec
+	return &Random{rand: rand.New(rand.NewSource(seed))}
+}
+
+// Next opens random closed cell
+func (r *Random) Next(_ context.Context, v game.View) (Action, error) {
+	snapshot := v.Snapshot()
+	var closed []game.CellView
+	for _, row := range snapshot.Cells {
+		for _, c := range row {
+			if c.State == game.CellClosed {
+				closed = append(closed, c)
+			}
+		}
+	}
+	if len(closed) == 0 {
+		return Action{}, errNoClosedCells
+	}
+	c := closed[r.rand.Intn(len(closed))]
+
+	return Action{Kind: Open, Row: c.Row, Col: c.Col}, nil
+}
@@ -0,0 +1,34 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandom(t *testing.T) {
+	b := newTestBoard(t)
+	_, err := b.Open([]int{2, 2})
+	require.NoError(t, err)
+
+	// the only closed cells left are the first row
+	seen := map[Action]bool{}
+	r := NewRandom(1)
+	for i := 0; i < 30; i++ {
+		a, err := r.Next(context.Background(), b)
+		require.NoError(t, err)
+		assert.Equal(t, Open, a.Kind)
+		assert.Equal(t, 0, a.Row)
+		seen[a] = true
+	}
+	assert.Len(t, seen, 3)
+
+	// the same seed makes the same choice
+	a, err := NewRandom(7).Next(context.Background(), b)
+	require.NoError(t, err)
+	again, err := NewRandom(7).Next(context.Background(), b)
+	require.NoError(t, err)
+	assert.Equal(t, a, again)
+}
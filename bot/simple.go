@@ -0,0 +1,65 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/proxx/game"
+)
+
+// Simple applies single cell rule: when number of opened cell equals number of flags around it, the rest of its
+// closed neighbours are safe and are opened by chord; when it equals number of closed and flagged neighbours,
+// all of them are black holes and are flagged. random cell is opened when the rule doesn't help
+type Simple struct {
+	random *Random
+}
+
+// NewSimple creates single cell rule strategy, seed is used by random guesses
+func NewSimple(seed int64) *Simple {
+	return &Simple{random: NewRandom(seed)}
+}
+
+// Next chords or flags around the first opened cell the rule works for, or opens random closed cell
+func (s *Simple) Next(ctx context.Context, v game.View) (Action, error) {
+	snapshot := v.Snapshot()
+	for _, row := range snapshot.Cells {
+		for _, c := range row {
+			if c.State != game.CellOpened || c.Value == 0 {
+				continue
+			}
+			var closed []game.CellView
+			var flags int
+			for _, n := range neighbours(snapshot, c) {
+				switch n.State {
+				case game.CellClosed:
+					closed = append(closed, n)
+				case game.CellFlagged:
+					flags++
+				}
+			}
+			switch {
+			case len(closed) == 0:
+			case flags == c.Value:
+				return Action{Kind: Chord, Row: c.Row, Col: c.Col}, nil
+			case flags+len(closed) == c.Value:
+				return Action{Kind: Flag, Row: closed[0].Row, Col: closed[0].Col}, nil
+			}
+		}
+	}
+
+	return s.random.Next(ctx, v)
+}
+
+// neighbours returns cells of the snapshot around c
+func neighbours(snapshot game.Snapshot, c game.CellView) []game.CellView {
+	cells := make([]game.CellView, 0, 8)
+	row, col := c.Row-snapshot.Top, c.Col-snapshot.Left
+	for i := row - 1; i <= row+1; i++ {
+		for j := col - 1; j <= col+1; j++ {
+			if (i != row || j != col) && i >= 0 && i < snapshot.Rows && j >= 0 && j < snapshot.Cols {
+				cells = append(cells, snapshot.Cells[i][j])
+			}
+		}
+	}
+
+	return cells
+}
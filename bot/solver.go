@@ -0,0 +1,385 @@
+package bot
+
+import (
+	"math"
+
+	"github.com/proxx/game"
+)
+
+// maxSearchSteps limits enumeration of one frontier component. component which needs more steps
+// is left without certain cells and its cells get the average probability
+const maxSearchSteps = 1 << 20
+
+// minEstimate keeps estimated probabilities away from certain 0 and 1
+const minEstimate = 1e-3
+
+// analysis tells which closed cells without flags are safe or hide black holes
+type analysis struct {
+	// cells are closed cells without flags
+	cells []game.CellView
+	// probability of black hole in every cell, 0 and 1 are certain
+	probability []float64
+}
+
+// safe returns cells which certainly have no black hole
+func (a analysis) safe() []game.CellView {
+	var safe []game.CellView
+	for i, p := range a.probability {
+		if p == 0 {
+			safe = append(safe, a.cells[i])
+		}
+	}
+
+	return safe
+}
+
+// constraint is opened cell telling how many black holes there are among its closed neighbours
+type constraint struct {
+	cells []int
+	holes int
+}
+
+// component is group of frontier cells connected by constraints, it is solved independently of other groups
+type component struct {
+	// cells are indices of analysis cells
+	cells       []int
+	constraints []constraint
+	// solutions[k] is number of ways to place k black holes into the cells,
+	// holes[k][i] is number of those ways with black hole in the cell i
+	solutions []float64
+	holes     [][]float64
+	// solved is false when the component is too big or no placement matches the constraints, e.g. some flag is wrong
+	solved bool
+}
+
+// analyse finds all placements of black holes matching numbers of opened cells, flags are trusted.
+// with all frontier components enumerated, the number of remaining black holes is taken into account as well
+func analyse(snapshot game.Snapshot) analysis {
+	var a analysis
+	index := make([][]int, snapshot.Rows)
+	for i, row := range snapshot.Cells {
+		index[i] = make([]int, snapshot.Cols)
+		for j, c := range row {
+			index[i][j] = -1
+			if c.State == game.CellClosed {
+				index[i][j] = len(a.cells)
+				a.cells = append(a.cells, c)
+			}
+		}
+	}
+	a.probability = make([]float64, len(a.cells))
+	if len(a.cells) == 0 {
+		return a
+	}
+
+	// constraints of every cell
+	var constraints []constraint
+	byCell := make([][]int, len(a.cells))
+	for _, row := range snapshot.Cells {
+		for _, c := range row {
+			if c.State != game.CellOpened || c.Value == 0 {
+				continue
+			}
+			con := constraint{holes: c.Value}
+			for _, n := range neighbours(snapshot, c) {
+				switch n.State {
+				case game.CellFlagged:
+					con.holes--
+				case game.CellClosed:
+					con.cells = append(con.cells, index[n.Row-snapshot.Top][n.Col-snapshot.Left])
+				}
+			}
+			if len(con.cells) == 0 {
+				continue
+			}
+			for _, i := range con.cells {
+				byCell[i] = append(byCell[i], len(constraints))
+			}
+			constraints = append(constraints, con)
+		}
+	}
+
+	components := splitComponents(constraints, byCell)
+	solved := true
+	for i := range components {
+		components[i].solve()
+		solved = solved && components[i].solved
+	}
+	if !solved || !a.weigh(components, snapshot.RemainingHoles) {
+		a.estimate(components, snapshot.RemainingHoles)
+	}
+
+	return a
+}
+
+// splitComponents groups frontier cells connected by constraints, cells are ordered by breadth-first search,
+// so constraints are completed early during enumeration
+func splitComponents(constraints []constraint, byCell [][]int) []component {
+	var components []component
+	visited := make([]bool, len(byCell))
+	seen := make([]bool, len(constraints))
+	local := make([]int, len(byCell))
+	for start := range byCell {
+		if visited[start] || len(byCell[start]) == 0 {
+			continue
+		}
+		var comp component
+		visited[start] = true
+		queue := []int{start}
+		for q := 0; q < len(queue); q++ {
+			for _, ci := range byCell[queue[q]] {
+				if seen[ci] {
+					continue
+				}
+				seen[ci] = true
+				comp.constraints = append(comp.constraints, constraints[ci])
+				for _, i := range constraints[ci].cells {
+					if !visited[i] {
+						visited[i] = true
+						queue = append(queue, i)
+					}
+				}
+			}
+		}
+		comp.cells = queue
+		for i, cell := range queue {
+			local[cell] = i
+		}
+		for i, con := range comp.constraints {
+			cells := make([]int, len(con.cells))
+			for j, cell := range con.cells {
+				cells[j] = local[cell]
+			}
+			comp.constraints[i].cells = cells
+		}
+		components = append(components, comp)
+	}
+
+	return components
+}
+
+// solve enumerates placements of black holes in the component
+func (c *component) solve() {
+	n := len(c.cells)
+	byCell := make([][]int, n)
+	assigned := make([]int, len(c.constraints))
+	left := make([]int, len(c.constraints))
+	for ci, con := range c.constraints {
+		left[ci] = len(con.cells)
+		for _, i := range con.cells {
+			byCell[i] = append(byCell[i], ci)
+		}
+	}
+	c.solutions = make([]float64, n+1)
+	c.holes = make([][]float64, n+1)
+	for k := range c.holes {
+		c.holes[k] = make([]float64, n)
+	}
+
+	hole := make([]bool, n)
+	var steps int
+	// search places black hole or nothing into cell i and goes on while constraints could be met,
+	// false is returned when the search is stopped
+	var search func(i, k int) bool
+	search = func(i, k int) bool {
+		if steps++; steps > maxSearchSteps {
+			return false
+		}
+		if i == n {
+			c.solutions[k]++
+			for j, h := range hole {
+				if h {
+					c.holes[k][j]++
+				}
+			}
+			return true
+		}
+		for _, h := range []bool{false, true} {
+			hole[i] = h
+			ok := true
+			for _, ci := range byCell[i] {
+				left[ci]--
+				if h {
+					assigned[ci]++
+				}
+				need := c.constraints[ci].holes
+				ok = ok && assigned[ci] <= need && assigned[ci]+left[ci] >= need
+			}
+			next := k
+			if h {
+				next++
+			}
+			completed := !ok || search(i+1, next)
+			for _, ci := range byCell[i] {
+				left[ci]++
+				if h {
+					assigned[ci]--
+				}
+			}
+			if !completed {
+				return false
+			}
+		}
+		hole[i] = false
+
+		return true
+	}
+
+	c.solved = search(0, 0) && c.total() > 0
+}
+
+// total returns number of all placements of black holes in the component
+func (c *component) total() float64 {
+	var total float64
+	for _, s := range c.solutions {
+		total += s
+	}
+
+	return total
+}
+
+// weigh counts placements of all remaining black holes, cells out of the frontier share holes left from it.
+// false is returned when no placement matches the number of remaining black holes
+func (a analysis) weigh(components []component, remaining int) bool {
+	frontier := make([]bool, len(a.cells))
+	others := len(a.cells)
+	for _, c := range components {
+		for _, cell := range c.cells {
+			frontier[cell] = true
+		}
+		others -= len(c.cells)
+	}
+
+	// certain cells are found by possible placements only, so tiny weights can't be mistaken for zero
+	possible := func(r int) float64 {
+		if r < 0 || r > others {
+			return 0
+		}
+		return 1
+	}
+	hole, safe := a.weights(components, frontier, others, remaining, possible)
+	var total float64
+	for i := range hole {
+		total += hole[i] + safe[i]
+	}
+	if total == 0 {
+		return false
+	}
+
+	// ways to place r holes out of the frontier, divided by the biggest of them to stay in range of float64
+	logWays := func(r int) float64 {
+		all, _ := math.Lgamma(float64(others + 1))
+		chosen, _ := math.Lgamma(float64(r + 1))
+		rest, _ := math.Lgamma(float64(others - r + 1))
+		return all - chosen - rest
+	}
+	top := math.Inf(-1)
+	for r := 0; r <= others; r++ {
+		top = math.Max(top, logWays(r))
+	}
+	ways := func(r int) float64 {
+		if r < 0 || r > others {
+			return 0
+		}
+		return math.Exp(logWays(r) - top)
+	}
+	holeWays, safeWays := a.weights(components, frontier, others, remaining, ways)
+
+	for i := range a.cells {
+		switch {
+		case hole[i] == 0:
+			a.probability[i] = 0
+		case safe[i] == 0:
+			a.probability[i] = 1
+		default:
+			a.probability[i] = holeWays[i] / (holeWays[i] + safeWays[i])
+		}
+	}
+
+	return true
+}
+
+// weights sums weights of placements with and without black hole in every cell,
+// placement with r holes out of the frontier is weighted by f(r)
+func (a analysis) weights(components []component, frontier []bool, others, remaining int,
+	f func(r int) float64) (hole, safe []float64) {
+	hole = make([]float64, len(a.cells))
+	safe = make([]float64, len(a.cells))
+	for j := range components {
+		rest := convolve(components, j)
+		c := &components[j]
+		for k, s := range c.solutions {
+			if s == 0 {
+				continue
+			}
+			var w float64
+			for t, ways := range rest {
+				w += ways * f(remaining-k-t)
+			}
+			for i, cell := range c.cells {
+				hole[cell] += c.holes[k][i] * w
+				safe[cell] += (s - c.holes[k][i]) * w
+			}
+		}
+	}
+	if others == 0 {
+		return hole, safe
+	}
+
+	for t, ways := range convolve(components, -1) {
+		r := remaining - t
+		w := ways * f(r)
+		if w == 0 {
+			continue
+		}
+		for i := range a.cells {
+			if !frontier[i] {
+				hole[i] += w * float64(r) / float64(others)
+				safe[i] += w * float64(others-r) / float64(others)
+			}
+		}
+	}
+
+	return hole, safe
+}
+
+// convolve returns number of placements with t black holes in all components but skipped one
+func convolve(components []component, skip int) []float64 {
+	ways := []float64{1}
+	for j, c := range components {
+		if j == skip {
+			continue
+		}
+		next := make([]float64, len(ways)+len(c.solutions)-1)
+		for t, w := range ways {
+			for k, s := range c.solutions {
+				next[t+k] += w * s
+			}
+		}
+		ways = next
+	}
+
+	return ways
+}
+
+// estimate is used when not all components are solved: solved ones give their own probabilities
+// and the rest of cells get the average one, which is never certain
+func (a analysis) estimate(components []component, remaining int) {
+	average := math.Min(math.Max(float64(remaining)/float64(len(a.cells)), minEstimate), 1-minEstimate)
+	for i := range a.probability {
+		a.probability[i] = average
+	}
+	for _, c := range components {
+		if !c.solved {
+			continue
+		}
+		total := c.total()
+		for i, cell := range c.cells {
+			var holes float64
+			for k := range c.solutions {
+				holes += c.holes[k][i]
+			}
+			a.probability[cell] = holes / total
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/proxx/game"
+	"github.com/stretchr/testify/assert"
+)
+
+// snapshotView is read-only board drawn with rows of symbols: '#' closed cell, 'F' flag, digit opened cell
+type snapshotView struct {
+	snapshot game.Snapshot
+}
+
+func newSnapshotView(remainingHoles int, rows ...string) snapshotView {
+	s := game.Snapshot{Rows: len(rows), Cols: len(rows[0]), RemainingHoles: remainingHoles}
+	s.Cells = make([][]game.CellView, len(rows))
+	for i, row := range rows {
+		for j, symbol := range row {
+			c := game.CellView{Row: i, Col: j}
+			switch symbol {
+			case '#':
+				c.State = game.CellClosed
+			case 'F':
+				c.State = game.CellFlagged
+				s.Flags++
+			default:
+				c.State = game.CellOpened
+				c.Value = int(symbol - '0')
+			}
+			s.Cells[i] = append(s.Cells[i], c)
+		}
+	}
+
+	return snapshotView{snapshot: s}
+}
+
+func (v snapshotView) Dimensions() (rows, cols int) {
+	return v.snapshot.Rows, v.snapshot.Cols
+}
+
+func (v snapshotView) Cell(row, col int) (game.CellView, error) {
+	return v.snapshot.Cells[row][col], nil
+}
+
+func (v snapshotView) Neighbours(row, col int) ([]game.CellView, error) {
+	return neighbours(v.snapshot, v.snapshot.Cells[row][col]), nil
+}
+
+func (v snapshotView) RemainingHoles() int {
+	return v.snapshot.RemainingHoles
+}
+
+func (v snapshotView) FlagsCount() int {
+	return v.snapshot.Flags
+}
+
+func (v snapshotView) Snapshot() game.Snapshot {
+	return v.snapshot
+}
+
+func TestAnalyse(t *testing.T) {
+	tests := []struct {
+		name            string
+		rows            []string
+		remainingHoles  int
+		wantProbability []float64
+	}{
+		{
+			name:            "deduction",
+			rows:            []string{"###", "111", "000"},
+			remainingHoles:  1,
+			wantProbability: []float64{0, 1, 0},
+		},
+		{
+			name:            "unopened",
+			rows:            []string{"###", "###"},
+			remainingHoles:  2,
+			wantProbability: []float64{1. / 3, 1. / 3, 1. / 3, 1. / 3, 1. / 3, 1. / 3},
+		},
+		{
+			name:            "remaining_holes",
+			rows:            []string{"#1##"},
+			remainingHoles:  1,
+			wantProbability: []float64{0.5, 0.5, 0},
+		},
+		{
+			name:            "frontier_and_the_rest",
+			rows:            []string{"#1###"},
+			remainingHoles:  2,
+			wantProbability: []float64{0.5, 0.5, 0.5, 0.5},
+		},
+		{
+			name:            "flags_are_trusted",
+			rows:            []string{"F2#"},
+			remainingHoles:  1,
+			wantProbability: []float64{1},
+		},
+		{
+			name:            "impossible",
+			rows:            []string{"2#"},
+			remainingHoles:  1,
+			wantProbability: []float64{1 - minEstimate},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := analyse(newSnapshotView(tt.remainingHoles, tt.rows...).snapshot)
+			assert.InDeltaSlice(t, tt.wantProbability, a.probability, 1e-9)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package bot
+
+import (
+	"fmt"
+	"sort"
+)
+
+// strategies are built-in strategies by name, seed makes strategies with random choices repeatable
+var strategies = map[string]func(seed int64) Strategy{
+	"random":      func(seed int64) Strategy { return NewRandom(seed) },
+	"simple":      func(seed int64) Strategy { return NewSimple(seed) },
+	"constraint":  func(seed int64) Strategy { return NewConstraint(seed) },
+	"probability": func(int64) Strategy { return NewProbability() },
+}
+
+// NewStrategy creates built-in strategy by name
+func NewStrategy(name string, seed int64) (Strategy, error) {
+	create, ok := strategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy %q, available: %v", name, StrategyNames())
+	}
+
+	return create(seed), nil
+}
+
+// StrategyNames returns names of built-in strategies
+func StrategyNames() []string {
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,108 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/proxx/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStrategy(t *testing.T) {
+	s, err := NewStrategy("random", 1)
+	require.NoError(t, err)
+	assert.IsType(t, &Random{}, s)
+
+	_, err = NewStrategy("oracle", 1)
+	assert.EqualError(t, err, `unknown strategy "oracle", available: [constraint probability random simple]`)
+}
+
+func TestStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy Strategy
+		rows     []string
+		holes    int
+		want     Action
+	}{
+		{name: "simple_chord", strategy: NewSimple(1), rows: []string{"F1#"}, holes: 0, want: Action{Kind: Chord, Col: 1}},
+		{name: "simple_flag", strategy: NewSimple(1), rows: []string{"#1"}, holes: 1, want: Action{Kind: Flag}},
+		{
+			name:     "simple_guess",
+			strategy: NewSimple(1),
+			rows:     []string{"#1##"},
+			holes:    1,
+			want:     Action{Kind: Open, Row: 0, Col: 3},
+		},
+		{
+			name:     "constraint_safe",
+			strategy: NewConstraint(1),
+			rows:     []string{"#1##"},
+			holes:    1,
+			want:     Action{Kind: Open, Row: 0, Col: 3},
+		},
+		{
+			name:     "constraint_avoids_holes",
+			strategy: NewConstraint(1),
+			rows:     []string{"#1##"},
+			holes:    2,
+			want:     Action{Kind: Open, Row: 0, Col: 2},
+		},
+		{
+			name:     "probability_safe",
+			strategy: NewProbability(),
+			rows:     []string{"###", "111", "000"},
+			holes:    1,
+			want:     Action{Kind: Open, Row: 0, Col: 0},
+		},
+		{
+			name:     "probability_guess",
+			strategy: NewProbability(),
+			rows:     []string{"#3##", "####"},
+			holes:    4,
+			want:     Action{Kind: Open, Row: 0, Col: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := tt.strategy.Next(context.Background(), newSnapshotView(tt.holes, tt.rows...))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, a)
+		})
+	}
+}
+
+func TestStrategies_NoClosedCells(t *testing.T) {
+	for _, name := range StrategyNames() {
+		s, err := NewStrategy(name, 1)
+		require.NoError(t, err)
+		_, err = s.Next(context.Background(), newSnapshotView(0, "1F"))
+		assert.ErrorIs(t, err, errNoClosedCells, name)
+	}
+}
+
+func TestStrategies_Play(t *testing.T) {
+	// boards are solved without guessing after the first click
+	boards := []struct {
+		holes [][]int
+		click []int
+	}{
+		{holes: [][]int{{0, 1}}, click: []int{2, 2}},
+		{holes: [][]int{{2, 1}}, click: []int{0, 0}},
+		{holes: [][]int{{0, 0}, {0, 2}}, click: []int{2, 1}},
+	}
+	for _, s := range []Strategy{NewConstraint(1), NewProbability()} {
+		// the same strategy plays several games in a row
+		for n, board := range boards {
+			b, err := game.NewBoardFromLayout(game.Layout{Rows: 3, Cols: 3, BlackHoles: board.holes})
+			require.NoError(t, err)
+			_, err = b.Open(board.click)
+			require.NoError(t, err)
+
+			stats, err := Play(context.Background(), b, s, n+1)
+			require.NoError(t, err)
+			assert.True(t, stats.Won, "%T game %d", s, n+1)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/proxx/bench"
+	"github.com/proxx/bot"
+	"github.com/proxx/game"
+	"github.com/spf13/cobra"
+)
+
+// benchOptions represents flags of bench command
+type benchOptions struct {
+	strategies  []string
+	bots        []string
+	presets     []string
+	games       int
+	seed        int64
+	workers     int
+	moveTimeout time.Duration
+}
+
+func benchCommand() *cobra.Command {
+	opts := &benchOptions{}
+
+	command := &cobra.Command{
+		Use:   "bench",
+		Short: "Compare bot strategies on the same seeded boards",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			presets := make([]game.Preset, 0, len(opts.presets))
+			for _, name := range opts.presets {
+				p, err := game.PresetByName(name)
+				if err != nil {
+					return err
+				}
+				presets = append(presets, p)
+			}
+			contenders, err := benchContenders(opts)
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("seed") {
+				opts.seed = time.Now().UnixNano()
+			}
+
+			// benchmark is stopped by Ctrl+C
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			fmt.Printf("Playing %d games per strategy and preset from seed %d on %d workers\n",
+				opts.games, opts.seed, opts.workers)
+			reports, err := bench.Run(ctx, bench.Config{
+				Presets:  presets,
+				Games:    opts.games,
+				Seed:     opts.seed,
+				Workers:  opts.workers,
+				Progress: printProgress,
+			}, contenders)
+			fmt.Println()
+			if err != nil {
+				return err
+			}
+			printReports(reports)
+
+			return nil
+		},
+	}
+
+	command.Flags().StringArrayVar(&opts.strategies, "strategy", nil,
+		fmt.Sprintf("built-in strategy, repeatable: %s", strings.Join(bot.StrategyNames(), ", ")))
+	command.Flags().StringArrayVar(&opts.bots, "bot", nil,
+		`command of external bot speaking the bot protocol, repeatable, e.g. "python3 bot.py"`)
+	command.Flags().StringSliceVar(&opts.presets, "presets", []string{"beginner"},
+		"comma separated board presets: beginner, intermediate, expert")
+	command.Flags().IntVar(&opts.games, "games", 1000, "number of games per strategy and preset")
+	command.Flags().Int64Var(&opts.seed, "seed", 0,
+		"seed of the first board, next boards use following seeds (random by default)")
+	command.Flags().IntVar(&opts.workers, "workers", runtime.NumCPU(), "number of games played at the same time")
+	command.Flags().DurationVar(&opts.moveTimeout, "move-timeout", bot.DefaultMoveTimeout,
+		"time external bot has to answer")
+
+	return command
+}
+
+// benchContenders creates contenders of built-in strategies and external bots, every worker gets its own bot process
+func benchContenders(opts *benchOptions) ([]bench.Contender, error) {
+	if len(opts.strategies)+len(opts.bots) == 0 {
+		return nil, errors.New("at least one --strategy or --bot is needed")
+	}
+
+	contenders := make([]bench.Contender, 0, len(opts.strategies)+len(opts.bots))
+	for _, name := range opts.strategies {
+		name := name
+		if _, err := bot.NewStrategy(name, 0); err != nil {
+			return nil, err
+		}
+		contenders = append(contenders, bench.Contender{
+			Name: name,
+			New: func(_ context.Context, worker int) (bot.Strategy, error) {
+				return bot.NewStrategy(name, opts.seed+int64(worker))
+			},
+		})
+	}
+	for _, command := range opts.bots {
+		args := strings.Fields(command)
+		if len(args) == 0 {
+			return nil, errors.New("bot command is empty")
+		}
+		contenders = append(contenders, bench.Contender{
+			Name: command,
+			New: func(ctx context.Context, _ int) (bot.Strategy, error) {
+				return bot.Start(ctx, args, bot.WithMoveTimeout(opts.moveTimeout))
+			},
+		})
+	}
+
+	return contenders, nil
+}
+
+func printProgress(played, total int) {
+	if played%100 == 0 || played == total {
+		fmt.Printf("\rPlayed %d of %d games", played, total)
+	}
+}
+
+func printReports(reports []bench.Report) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STRATEGY\tPRESET\tGAMES\tWIN RATE\t95% CI\tAVG TIME\t95% CI\tAVG 3BV/S\t95% CI\tFAILED")
+	for _, r := range reports {
+		avg, low, high := r.Time.Duration()
+		fmt.Fprintf(w, "%s\t%s\t%d\t%.1f%%\t%.1f%%-%.1f%%\t%v\t%v-%v\t%.2f\t%.2f-%.2f\t%d\n",
+			r.Contender, r.Preset, r.Games, 100*r.WinRate.Mean, 100*r.WinRate.Low, 100*r.WinRate.High,
+			avg.Round(time.Microsecond), low.Round(time.Microsecond), high.Round(time.Microsecond),
+			r.BBBVPerSecond.Mean, r.BBBVPerSecond.Low, r.BBBVPerSecond.High, r.Failed)
+	}
+	w.Flush()
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/proxx/bot"
+	"github.com/proxx/game"
+	"github.com/spf13/cobra"
+)
+
+// botRunOptions represents flags of bot-run command
+type botRunOptions struct {
+	games       int
+	preset      string
+	seed        int64
+	moveTimeout time.Duration
+}
+
+func botRun() *cobra.Command {
+	opts := &botRunOptions{}
+
+	command := &cobra.Command{
+		Use:   "bot-run <command> [args...]",
+		Short: "Play games by external bot speaking the bot protocol over stdin and stdout",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			preset, err := game.PresetByName(opts.preset)
+			if err != nil {
+				return err
+			}
+			if opts.games <= 0 {
+				return errors.New("number of games must be positive")
+			}
+			if !cmd.Flags().Changed("seed") {
+				opts.seed = time.Now().UnixNano()
+			}
+
+			// bot is stopped by Ctrl+C
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			p, err := bot.Start(ctx, args, bot.WithMoveTimeout(opts.moveTimeout))
+			if err != nil {
+				return err
+			}
+			err = runBot(ctx, p, preset, opts)
+			closeErr := p.Close()
+			if err != nil {
+				return err
+			}
+			if closeErr != nil {
+				fmt.Printf("Notice: bot has exited with error: %v\n", closeErr)
+			}
+
+			return nil
+		},
+	}
+	// flags after the bot command belong to the bot
+	command.Flags().SetInterspersed(false)
+
+	command.Flags().IntVar(&opts.games, "games", 10, "number of games to play")
+	command.Flags().StringVar(&opts.preset, "preset", "beginner", "board preset: beginner, intermediate or expert")
+	command.Flags().Int64Var(&opts.seed, "seed", 0,
+		"seed of the first board, next boards use following seeds (random by default)")
+	command.Flags().DurationVar(&opts.moveTimeout, "move-timeout", bot.DefaultMoveTimeout, "time bot has to answer")
+
+	return command
+}
+
+// runBot plays games by the bot and prints their results
+func runBot(ctx context.Context, p *bot.Process, preset game.Preset, opts *botRunOptions) error {
+	name := p.Name()
+	if name == "" {
+		name = "bot"
+	}
+	fmt.Printf("Bot %s plays %d %s games from seed %d\n", name, opts.games, preset.Name, opts.seed)
+
+	var won int
+	var wonTime time.Duration
+	for i := 1; i <= opts.games; i++ {
+		seed := opts.seed + int64(i-1)
+		b, err := game.NewSeededBoard(preset.Size, preset.BlackHoles, seed)
+		if err != nil {
+			return err
+		}
+		stats, err := bot.Play(ctx, b, p, i)
+		if err != nil {
+			return fmt.Errorf("game %d (seed %d): %w", i, seed, err)
+		}
+		result := "lost"
+		if stats.Won {
+			result = "won"
+			won++
+			wonTime += stats.Duration()
+		}
+		fmt.Printf("Game %d (seed %d): %s, moves: %d, cleared: %d, 3BV: %d, time: %v\n",
+			i, seed, result, stats.Moves, stats.Cleared, stats.BBBV, stats.Duration().Round(time.Millisecond))
+	}
+
+	fmt.Printf("Won %d of %d games (%.1f%%)", won, opts.games, 100*float64(won)/float64(opts.games))
+	if won > 0 {
+		fmt.Printf(", average time of won game: %v", (wonTime / time.Duration(won)).Round(time.Millisecond))
+	}
+	fmt.Println()
+
+	return nil
+}
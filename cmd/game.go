@@ -1,43 +1,223 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
 
 	"github.com/proxx/game"
+	"github.com/proxx/records"
+	"github.com/proxx/replay"
+	"github.com/proxx/share"
 	"github.com/spf13/cobra"
 )
 
+const (
+	defaultDensity = 0.15
+
+	// replays are saved to replaysDir of records directory
+	replaysDir = "replays"
+	replayExt  = ".pxr"
+)
+
+// startOptions represents flags of start command
+type startOptions struct {
+	infinite           bool
+	seed               int64
+	density            float64
+	minimap            bool
+	viewRows, viewCols int
+	moveTimeout        time.Duration
+	onTimeout          string
+	clock              time.Duration
+	preset             string
+	player             string
+	recordsDir         string
+	noRecord           bool
+	replay             string
+	code               string
+	// profile is name results are grouped by, it is set when board is created
+	profile string
+}
+
 func start() *cobra.Command {
+	opts := &startOptions{}
+
 	command := &cobra.Command{
 		Use:   "start",
 		Short: "Start the game",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			var (
-				cellNumber, blackHoles int
-			)
-
-			fmt.Print("Enter board N size:")
-			_, err := fmt.Scanln(&cellNumber)
+			b, err := newBoard(cmd, opts)
 			if err != nil {
 				return err
 			}
-
-			fmt.Print("Enter number of black holes:")
-			_, err = fmt.Scanln(&blackHoles)
+			gameInstance, err := newGame(cmd, b, opts)
 			if err != nil {
 				return err
 			}
 
-			b, err := game.NewBoard(cellNumber, blackHoles)
-			if err != nil {
-				return err
-			}
-			gameInstance := game.NewGame(b)
+			// game is interrupted by Ctrl+C
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
 
-			return gameInstance.Start(os.Stdin)
+			return gameInstance.Run(ctx, os.Stdin)
 		},
 	}
 
+	command.Flags().BoolVar(&opts.infinite, "infinite", false, "play on infinite board generated while exploring")
+	command.Flags().Int64Var(&opts.seed, "seed", 0, "seed of the board (random by default)")
+	command.Flags().Float64Var(&opts.density, "density", defaultDensity, "black holes density of infinite board")
+	command.Flags().BoolVar(&opts.minimap, "minimap", false, "print minimap of the board")
+	command.Flags().IntVar(&opts.viewRows, "view-rows", 0, "number of visible board rows")
+	command.Flags().IntVar(&opts.viewCols, "view-cols", 0, "number of visible board columns")
+	command.Flags().DurationVar(&opts.moveTimeout, "move-timeout", 0, "time limit of every move, e.g. 30s")
+	command.Flags().StringVar(&opts.onTimeout, "on-timeout", string(game.TimeoutForfeit),
+		"what happens when move time is over: forfeit or random")
+	command.Flags().DurationVar(&opts.clock, "clock", 0, "time limit of the whole game, e.g. 10m")
+	command.Flags().StringVar(&opts.preset, "preset", "", "board preset: beginner, intermediate or expert")
+	command.Flags().StringVar(&opts.player, "player", defaultPlayer(), "player name results are saved with")
+	command.Flags().StringVar(&opts.recordsDir, "records-dir", "", "directory of results (user config directory by default)")
+	command.Flags().BoolVar(&opts.noRecord, "no-record", false, "don't save result and replay of the game")
+	command.Flags().StringVar(&opts.code, "code", "", "play board shared by code, see share command")
+	command.Flags().StringVar(&opts.replay, "replay", "", "file replay of the game is saved to (records directory by default)")
+
 	return command
 }
+
+// newBoard creates infinite board or asks user for size of the board
+func newBoard(cmd *cobra.Command, opts *startOptions) (*game.Board, error) {
+	if opts.code != "" {
+		shared, err := share.DecodeBoard(opts.code)
+		if err != nil {
+			return nil, err
+		}
+		opts.profile = shared.Profile()
+
+		return shared.NewBoard()
+	}
+	if opts.infinite {
+		if !cmd.Flags().Changed("seed") {
+			opts.seed = time.Now().UnixNano()
+		}
+		b, err := game.NewInfiniteBoard(opts.seed, opts.density)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Printf("Infinite board, seed %d\n", opts.seed)
+		opts.profile = game.InfiniteProfile
+
+		return b, nil
+	}
+	if opts.preset != "" {
+		p, err := game.PresetByName(opts.preset)
+		if err != nil {
+			return nil, err
+		}
+		opts.profile = p.Name
+
+		return newFixedBoard(cmd, opts, p.Size, p.BlackHoles)
+	}
+
+	var (
+		cellNumber, blackHoles int
+	)
+
+	fmt.Print("Enter board N size:")
+	_, err := fmt.Scanln(&cellNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Print("Enter number of black holes:")
+	_, err = fmt.Scanln(&blackHoles)
+	if err != nil {
+		return nil, err
+	}
+	opts.profile = game.ProfileName(cellNumber, blackHoles)
+
+	return newFixedBoard(cmd, opts, cellNumber, blackHoles)
+}
+
+// newFixedBoard creates board of fixed size with seed from flags or random one
+func newFixedBoard(cmd *cobra.Command, opts *startOptions, size, blackHoles int) (*game.Board, error) {
+	if !cmd.Flags().Changed("seed") {
+		return game.NewBoard(size, blackHoles)
+	}
+
+	return game.NewSeededBoard(size, blackHoles, opts.seed)
+}
+
+// newGame applies view flags to the board and creates game on it
+func newGame(cmd *cobra.Command, b *game.Board, opts *startOptions) (*game.Game, error) {
+	if cmd.Flags().Changed("view-rows") || cmd.Flags().Changed("view-cols") {
+		v := b.Viewport()
+		if cmd.Flags().Changed("view-rows") {
+			v.Rows = opts.viewRows
+		}
+		if cmd.Flags().Changed("view-cols") {
+			v.Cols = opts.viewCols
+		}
+		err := b.SetViewSize(v.Rows, v.Cols)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var gameOpts []game.Option
+	if opts.minimap {
+		gameOpts = append(gameOpts, game.WithMinimap())
+	}
+	if opts.moveTimeout > 0 {
+		action, err := game.ParseTimeoutAction(opts.onTimeout)
+		if err != nil {
+			return nil, err
+		}
+		gameOpts = append(gameOpts, game.WithMoveTimeout(opts.moveTimeout, action))
+	}
+	if opts.clock > 0 {
+		gameOpts = append(gameOpts, game.WithGameClock(opts.clock))
+	}
+	if opts.noRecord {
+		return game.NewGame(b, gameOpts...), nil
+	}
+
+	dir, err := recordsDir(opts.recordsDir)
+	if err != nil {
+		return nil, err
+	}
+	replayPath, err := newReplayPath(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+	recorder, err := replay.NewRecorder(b, replayPath, opts.player, opts.profile)
+	if err != nil {
+		return nil, err
+	}
+	scores := filepath.Join(dir, records.ScoresFile)
+	history := filepath.Join(dir, records.HistoryFile)
+	gameOpts = append(gameOpts,
+		game.WithRecorder(records.NewLeaderboard(scores, opts.player, opts.profile)),
+		game.WithRecorder(records.NewHistory(history, opts.player, opts.profile)),
+		game.WithRecorder(recorder),
+	)
+
+	return game.NewGame(recorder, gameOpts...), nil
+}
+
+// newReplayPath returns path replay is saved to: set by flag or new file in replays directory
+func newReplayPath(dir string, opts *startOptions) (string, error) {
+	if opts.replay != "" {
+		return opts.replay, nil
+	}
+	dir = filepath.Join(dir, replaysDir)
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s-%s%s", time.Now().Format("20060102-150405.000"), opts.profile, replayExt)
+
+	return filepath.Join(dir, name), nil
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/proxx/game"
+	"github.com/spf13/cobra"
+)
+
+// generateOptions represents flags of generate command
+type generateOptions struct {
+	size, blackHoles int
+	seed             int64
+	stats            bool
+	preset           string
+}
+
+func generate() *cobra.Command {
+	opts := &generateOptions{}
+
+	command := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate the board and print its layout",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if opts.preset != "" {
+				p, err := game.PresetByName(opts.preset)
+				if err != nil {
+					return err
+				}
+				opts.size, opts.blackHoles = p.Size, p.BlackHoles
+			}
+			if opts.size <= 0 {
+				return errors.New("board size must be positive")
+			}
+			if !cmd.Flags().Changed("seed") {
+				opts.seed = time.Now().UnixNano()
+			}
+			b, err := game.NewSeededBoard(opts.size, opts.blackHoles, opts.seed)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Board %dx%d, black holes %d, seed %d\n", opts.size, opts.size, opts.blackHoles, opts.seed)
+			err = b.RenderLayout(os.Stdout)
+			if err != nil {
+				return err
+			}
+			if !opts.stats {
+				return nil
+			}
+
+			m, err := b.Metrics()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("3BV: %d\nOpenings: %d\nIsolated cells: %d\nZero cells: %d\n",
+				m.BBBV, m.Openings, m.IsolatedCells, m.ZeroCells)
+
+			return nil
+		},
+	}
+
+	command.Flags().IntVar(&opts.size, "size", 10, "number of cells on the board side")
+	command.Flags().IntVar(&opts.blackHoles, "holes", 10, "number of black holes")
+	command.Flags().Int64Var(&opts.seed, "seed", 0, "seed of the board (random by default)")
+	command.Flags().StringVar(&opts.preset, "preset", "", "board preset: beginner, intermediate or expert")
+	command.Flags().BoolVar(&opts.stats, "stats", false, "print difficulty metrics of the board")
+
+	return command
+}
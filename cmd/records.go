@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/proxx/records"
+)
+
+const anonymousPlayer = "player"
+
+// recordsDir returns directory of results set by flag or the default one
+func recordsDir(flag string) (string, error) {
+	if flag != "" {
+		return flag, nil
+	}
+
+	return records.DefaultDir()
+}
+
+// defaultPlayer returns name of the current user
+func defaultPlayer() string {
+	for _, env := range []string{"USER", "USERNAME"} {
+		if name := os.Getenv(env); name != "" {
+			return name
+		}
+	}
+
+	return anonymousPlayer
+}
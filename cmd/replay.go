@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/proxx/replay"
+	"github.com/proxx/share"
+	"github.com/spf13/cobra"
+)
+
+const replayPrompt = "Enter for the next move, b for the previous one, j <move> to jump, p to play the rest, q to quit:"
+
+// replayOptions represents flags of replay command
+type replayOptions struct {
+	speed float64
+	move  int
+	step  bool
+}
+
+func replayCommand() *cobra.Command {
+	opts := &replayOptions{}
+
+	command := &cobra.Command{
+		Use:   "replay <file or code>",
+		Short: "Play back recorded game",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := loadReplay(args[0])
+			if err != nil {
+				return err
+			}
+			p, err := replay.NewPlayer(r)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Game of %s (%s) played %s, seed %d, %d moves\n", r.Player, r.Profile,
+				r.StartedAt.Local().Format(time.RFC822), r.Seed, p.Len())
+
+			if cmd.Flags().Changed("move") {
+				err = p.Seek(opts.move)
+				if err != nil {
+					return err
+				}
+				printPosition(p, nil)
+				if !opts.step {
+					return nil
+				}
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			if opts.step {
+				return stepReplay(ctx, p, opts.speed, os.Stdin)
+			}
+
+			return playReplay(ctx, p, opts.speed)
+		},
+	}
+
+	command.Flags().Float64Var(&opts.speed, "speed", 1, "playback speed, 2 is twice faster, 0 is without pauses")
+	command.Flags().IntVar(&opts.move, "move", 0, "jump to the state after given move")
+	command.Flags().BoolVar(&opts.step, "step", false, "go through the game move by move")
+
+	return command
+}
+
+// loadReplay reads replay from the file or decodes replay code if there is no such file
+func loadReplay(fileOrCode string) (*replay.Replay, error) {
+	r, err := replay.Load(fileOrCode)
+	if errors.Is(err, os.ErrNotExist) && strings.HasPrefix(fileOrCode, "r") {
+		return share.DecodeReplay(fileOrCode)
+	}
+
+	return r, err
+}
+
+// playReplay plays the rest of the game printing the board after every move
+func playReplay(ctx context.Context, p *replay.Player, speed float64) error {
+	if p.Position() == 0 {
+		printPosition(p, nil)
+	}
+
+	return p.Play(ctx, speed, func(a replay.Action) {
+		printPosition(p, &a)
+	})
+}
+
+// stepReplay goes through the game by player commands
+func stepReplay(ctx context.Context, p *replay.Player, speed float64, in io.Reader) error {
+	if p.Position() == 0 {
+		printPosition(p, nil)
+	}
+	reader := bufio.NewReader(in)
+	for {
+		fmt.Print(replayPrompt)
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			var a replay.Action
+			a, err = p.Next()
+			if errors.Is(err, io.EOF) {
+				fmt.Println("Notice: it was the last move.")
+				continue
+			}
+			if err == nil {
+				printPosition(p, &a)
+			}
+		case fields[0] == "b":
+			err = seekAndPrint(p, p.Position()-1)
+		case fields[0] == "j" && len(fields) == 2:
+			var move int
+			move, err = strconv.Atoi(fields[1])
+			if err == nil {
+				err = seekAndPrint(p, move)
+			}
+		case fields[0] == "p":
+			err = playReplay(ctx, p, speed)
+		case fields[0] == "q":
+			return nil
+		default:
+			err = fmt.Errorf("unknown command %q", strings.TrimSpace(line))
+		}
+		if err != nil {
+			fmt.Printf("Notice: %v.\n", err)
+		}
+	}
+}
+
+func seekAndPrint(p *replay.Player, move int) error {
+	err := p.Seek(move)
+	if err != nil {
+		return err
+	}
+	printPosition(p, nil)
+
+	return nil
+}
+
+// printPosition prints the board and the last applied action
+func printPosition(p *replay.Player, a *replay.Action) {
+	if a != nil {
+		fmt.Printf("Move %d/%d at %v: %v\n", p.Position(), p.Len(), a.At.Round(time.Millisecond), a)
+	} else {
+		fmt.Printf("Move %d/%d\n", p.Position(), p.Len())
+	}
+	p.Board().Print()
+	fmt.Printf("Board is %s\n", p.Board().State())
+}
@@ -13,6 +13,16 @@ func Execute() error {
 	}
 
 	command.AddCommand(start())
+	command.AddCommand(generate())
+	command.AddCommand(scores())
+	command.AddCommand(stats())
+	command.AddCommand(replayCommand())
+	command.AddCommand(verify())
+	command.AddCommand(shareCommand())
+	command.AddCommand(serve())
+	command.AddCommand(versusCommand())
+	command.AddCommand(botRun())
+	command.AddCommand(benchCommand())
 
 	return command.Execute()
 }
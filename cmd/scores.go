@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/proxx/records"
+	"github.com/spf13/cobra"
+)
+
+const dateLayout = "2006-01-02"
+
+// scoresOptions represents flags of scores command
+type scoresOptions struct {
+	profile    string
+	player     string
+	by         string
+	top        int
+	unassisted bool
+	since      string
+	recordsDir string
+}
+
+func scores() *cobra.Command {
+	opts := &scoresOptions{}
+
+	command := &cobra.Command{
+		Use:   "scores",
+		Short: "Show the best results",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			by, err := records.ParseSortBy(opts.by)
+			if err != nil {
+				return err
+			}
+			filter := records.Filter{Profile: opts.profile, Player: opts.player, Unassisted: opts.unassisted}
+			if opts.since != "" {
+				filter.Since, err = time.ParseInLocation(dateLayout, opts.since, time.Local)
+				if err != nil {
+					return fmt.Errorf("since date must be in YYYY-MM-DD format: %w", err)
+				}
+			}
+			dir, err := recordsDir(opts.recordsDir)
+			if err != nil {
+				return err
+			}
+
+			top, err := records.NewLeaderboard(filepath.Join(dir, records.ScoresFile), "", "").Top(filter, by, opts.top)
+			if err != nil {
+				return err
+			}
+			if len(top) == 0 {
+				fmt.Println("There are no results yet")
+				return nil
+			}
+			printScores(top)
+
+			return nil
+		},
+	}
+
+	command.Flags().StringVar(&opts.profile, "profile", "", "preset or custom board profile, e.g. beginner")
+	command.Flags().StringVar(&opts.player, "player", "", "show results of the player only")
+	command.Flags().StringVar(&opts.by, "by", string(records.ByTime), "order of results: time or efficiency")
+	command.Flags().IntVar(&opts.top, "top", 10, "number of results to show, all if zero")
+	command.Flags().BoolVar(&opts.unassisted, "unassisted", false, "show games without hints and undos only")
+	command.Flags().StringVar(&opts.since, "since", "", "show games played since the date, YYYY-MM-DD")
+	command.Flags().StringVar(&opts.recordsDir, "records-dir", "", "directory of results (user config directory by default)")
+
+	return command
+}
+
+func printScores(scores []records.Score) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tPLAYER\tPROFILE\tTIME\tEFFICIENCY\tSCORE\tASSISTED\tDATE\tSEED")
+	for i, s := range scores {
+		assisted := "no"
+		if s.Assisted() {
+			assisted = fmt.Sprintf("hints %d, undos %d", s.HintsUsed, s.UndosUsed)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%v\t%.0f%%\t%d\t%s\t%s\t%d\n", i+1, s.Player, s.Profile,
+			s.Duration.Round(time.Millisecond), s.Efficiency*100, s.Score, assisted,
+			s.Date.Local().Format(dateLayout), s.Seed)
+	}
+	w.Flush()
+}
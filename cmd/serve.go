@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/proxx/server"
+	"github.com/spf13/cobra"
+)
+
+// shutdownTimeout is time given to requests in progress when server is stopped
+const shutdownTimeout = 5 * time.Second
+
+func serve() *cobra.Command {
+	var (
+		addr          string
+		ttl           time.Duration
+		maxSessions   int
+		spectateDelay time.Duration
+	)
+
+	command := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve games over HTTP API and browser frontend",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if spectateDelay < 0 || spectateDelay > server.MaxSpectateDelay {
+				return fmt.Errorf("spectate delay must be from 0 to %v", server.MaxSpectateDelay)
+			}
+			srv := &http.Server{
+				Addr: addr,
+				Handler: server.New(server.WithTTL(ttl), server.WithMaxSessions(maxSessions),
+					server.WithSpectateDelay(spectateDelay)),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			// server is stopped by Ctrl+C
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			go func() {
+				<-ctx.Done()
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+				defer cancel()
+				_ = srv.Shutdown(shutdownCtx)
+			}()
+
+			fmt.Printf("Serving games at %s, open http://%s to play in browser\n", addr, addr)
+			err := srv.ListenAndServe()
+			if errors.Is(err, http.ErrServerClosed) {
+				return nil
+			}
+
+			return err
+		},
+	}
+
+	command.Flags().StringVar(&addr, "addr", "localhost:8080", "address to listen on")
+	command.Flags().DurationVar(&ttl, "ttl", server.DefaultTTL, "time game is kept after the last request to it")
+	command.Flags().IntVar(&maxSessions, "max-games", server.DefaultMaxSessions, "number of games played at the same time")
+	command.Flags().DurationVar(&spectateDelay, "spectate-delay", 0,
+		"delay of messages sent to spectators of every game, up to 10m")
+
+	return command
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/proxx/game"
+	"github.com/proxx/replay"
+	"github.com/proxx/share"
+	"github.com/spf13/cobra"
+)
+
+// shareOptions represents flags of share command
+type shareOptions struct {
+	preset           string
+	size, blackHoles int
+	seed             int64
+	infinite         bool
+	density          float64
+	layout           bool
+	replay           string
+	withMoves        bool
+}
+
+func shareCommand() *cobra.Command {
+	opts := &shareOptions{}
+
+	command := &cobra.Command{
+		Use:   "share",
+		Short: "Print code of the board to play it with teammates",
+		Long: "Print code of the board to play it with teammates. Board is played with start --code <code>, " +
+			"replay code is played back with replay <code>",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if opts.replay != "" {
+				return shareReplay(opts)
+			}
+
+			b, err := sharedBoard(cmd, opts)
+			if err != nil {
+				return err
+			}
+			code, err := share.EncodeBoard(b)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Code of %s board: %s\n", b.Profile(), code)
+
+			return nil
+		},
+	}
+
+	command.Flags().StringVar(&opts.preset, "preset", "", "board preset: beginner, intermediate or expert")
+	command.Flags().IntVar(&opts.size, "size", 10, "number of cells on the board side")
+	command.Flags().IntVar(&opts.blackHoles, "holes", 10, "number of black holes")
+	command.Flags().Int64Var(&opts.seed, "seed", 0, "seed of the board (random by default)")
+	command.Flags().BoolVar(&opts.infinite, "infinite", false, "share infinite board")
+	command.Flags().Float64Var(&opts.density, "density", defaultDensity, "black holes density of infinite board")
+	command.Flags().BoolVar(&opts.layout, "layout", false,
+		"pack positions of black holes instead of seed, so code doesn't depend on the board generator")
+	command.Flags().StringVar(&opts.replay, "replay", "", "share board of the recorded game")
+	command.Flags().BoolVar(&opts.withMoves, "with-moves", false, "share the whole recorded game, not only its board")
+
+	return command
+}
+
+// sharedBoard describes board set by flags
+func sharedBoard(cmd *cobra.Command, opts *shareOptions) (share.Board, error) {
+	if !cmd.Flags().Changed("seed") {
+		opts.seed = time.Now().UnixNano()
+	}
+	if opts.infinite {
+		return share.Board{Infinite: true, Seed: opts.seed, Density: opts.density}, nil
+	}
+	if opts.preset != "" {
+		p, err := game.PresetByName(opts.preset)
+		if err != nil {
+			return share.Board{}, err
+		}
+		opts.size, opts.blackHoles = p.Size, p.BlackHoles
+	}
+	b := share.Board{Size: opts.size, BlackHoles: opts.blackHoles, Seed: opts.seed}
+	if !opts.layout {
+		return b, nil
+	}
+
+	generated, err := b.NewBoard()
+	if err != nil {
+		return share.Board{}, err
+	}
+	l, err := generated.Layout()
+	if err != nil {
+		return share.Board{}, err
+	}
+
+	return share.Board{Layout: &l}, nil
+}
+
+// shareReplay prints code of the recorded game or its board
+func shareReplay(opts *shareOptions) error {
+	r, err := replay.Load(opts.replay)
+	if err != nil {
+		return err
+	}
+	if opts.withMoves {
+		code, err := share.EncodeReplay(r)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Code of the game: %s\n", code)
+
+		return nil
+	}
+
+	b := share.Board{Layout: r.Layout}
+	if r.Infinite() {
+		b = share.Board{Infinite: true, Seed: r.Seed, Density: r.Density}
+	}
+	code, err := share.EncodeBoard(b)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Code of %s board: %s\n", b.Profile(), code)
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/proxx/records"
+	"github.com/spf13/cobra"
+)
+
+// histogramWidth is length of the longest bar of losses histogram
+const histogramWidth = 30
+
+// statsOptions represents flags of stats command
+type statsOptions struct {
+	profile    string
+	player     string
+	since      string
+	export     string
+	output     string
+	recordsDir string
+}
+
+func stats() *cobra.Command {
+	opts := &statsOptions{}
+
+	command := &cobra.Command{
+		Use:   "stats",
+		Short: "Show lifetime statistics of games",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			filter := records.Filter{Profile: opts.profile, Player: opts.player}
+			if opts.since != "" {
+				var err error
+				filter.Since, err = time.ParseInLocation(dateLayout, opts.since, time.Local)
+				if err != nil {
+					return fmt.Errorf("since date must be in YYYY-MM-DD format: %w", err)
+				}
+			}
+			dir, err := recordsDir(opts.recordsDir)
+			if err != nil {
+				return err
+			}
+
+			entries, err := records.NewHistory(filepath.Join(dir, records.HistoryFile), "", "").Entries(filter)
+			if err != nil {
+				return err
+			}
+			if opts.export != "" {
+				return exportHistory(entries, opts)
+			}
+			if len(entries) == 0 {
+				fmt.Println("There are no games yet")
+				return nil
+			}
+			printSummary(records.Summarize(entries))
+
+			return nil
+		},
+	}
+
+	command.Flags().StringVar(&opts.profile, "profile", "", "preset or custom board profile, e.g. beginner")
+	command.Flags().StringVar(&opts.player, "player", "", "show games of the player only")
+	command.Flags().StringVar(&opts.since, "since", "", "show games played since the date, YYYY-MM-DD")
+	command.Flags().StringVar(&opts.export, "export", "", "export games instead of statistics: csv or json")
+	command.Flags().StringVar(&opts.output, "output", "", "file games are exported to (stdout by default)")
+	command.Flags().StringVar(&opts.recordsDir, "records-dir", "", "directory of results (user config directory by default)")
+
+	return command
+}
+
+// exportHistory writes games to output file or stdout
+func exportHistory(entries []records.Entry, opts *statsOptions) error {
+	format, err := records.ParseExportFormat(opts.export)
+	if err != nil {
+		return err
+	}
+	if opts.output == "" {
+		return records.Export(os.Stdout, entries, format)
+	}
+
+	f, err := os.Create(opts.output)
+	if err != nil {
+		return err
+	}
+	err = records.Export(f, entries, format)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func printSummary(s records.Summary) {
+	fmt.Printf("Games played: %d, won: %d (%.0f%%)\n", s.Played, s.Won, s.WinRate()*100)
+	fmt.Printf("Current streak: %d, longest streak: %d\n\n", s.CurrentStreak, s.LongestStreak)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROFILE\tPLAYED\tWON\tAVERAGE TIME")
+	for _, p := range s.Profiles {
+		average := "-"
+		if p.Won > 0 {
+			average = p.AverageTime.Round(time.Second).String()
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", p.Profile, p.Played, p.Won, average)
+	}
+	w.Flush()
+
+	fmt.Println("\nLosses:")
+	maxCount := 0
+	for _, b := range s.Losses {
+		if b.Count > maxCount {
+			maxCount = b.Count
+		}
+	}
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, b := range s.Losses {
+		bar := 0
+		if maxCount > 0 {
+			bar = b.Count * histogramWidth / maxCount
+		}
+		fmt.Fprintf(w, "%s\t%s %d\n", b.Name, strings.Repeat("#", bar), b.Count)
+	}
+	w.Flush()
+}
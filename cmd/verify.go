@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/proxx/records"
+	"github.com/proxx/replay"
+	"github.com/spf13/cobra"
+)
+
+func verify() *cobra.Command {
+	var submit string
+
+	command := &cobra.Command{
+		Use:   "verify <replay file or code>",
+		Short: "Check recorded game against the engine",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := loadReplay(args[0])
+			if err != nil {
+				return err
+			}
+			v, err := replay.Verify(r)
+			if err != nil {
+				return err
+			}
+			stats := v.Stats
+			fmt.Printf("Replay is valid: %s (%s), board is %s in %v, score %d\n", r.Player, r.Profile, v.State,
+				stats.Duration().Round(time.Millisecond), stats.Score)
+			if submit == "" {
+				return nil
+			}
+			if !stats.Won {
+				return errors.New("only won games are accepted to the leaderboard")
+			}
+
+			// scores of verified games are accepted to shared leaderboard under the recorded player name
+			notes, err := records.NewLeaderboard(submit, r.Player, r.Profile).Record(stats)
+			if err != nil {
+				return err
+			}
+			fmt.Println("Score is added to the leaderboard")
+			for _, note := range notes {
+				fmt.Println(note)
+			}
+
+			return nil
+		},
+	}
+
+	command.Flags().StringVar(&submit, "submit", "", "leaderboard file the verified score is added to")
+
+	return command
+}
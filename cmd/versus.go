@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/proxx/game"
+	"github.com/proxx/versus"
+	"github.com/spf13/cobra"
+)
+
+func versusCommand() *cobra.Command {
+	opts := &startOptions{}
+	var (
+		players []string
+		rule    string
+	)
+
+	command := &cobra.Command{
+		Use:   "versus",
+		Short: "Play turn-based match of several players on one board at one terminal",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			r, err := versus.ParseRule(rule)
+			if err != nil {
+				return err
+			}
+			b, err := newBoard(cmd, opts)
+			if err != nil {
+				return err
+			}
+			m, err := versus.New(b, r, players...)
+			if err != nil {
+				return err
+			}
+
+			// match is interrupted by Ctrl+C
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			return game.NewGame(m).Run(ctx, os.Stdin)
+		},
+	}
+
+	command.Flags().StringSliceVar(&players, "players", []string{"player1", "player2"},
+		"names of players in order of turns")
+	command.Flags().StringVar(&rule, "rule", string(versus.Eliminate),
+		"what happens to player who opens black hole: eliminate or penalty")
+	command.Flags().Int64Var(&opts.seed, "seed", 0, "seed of the board (random by default)")
+	command.Flags().StringVar(&opts.preset, "preset", "", "board preset: beginner, intermediate or expert")
+	command.Flags().StringVar(&opts.code, "code", "", "play board shared by code, see share command")
+
+	return command
+}
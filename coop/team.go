@@ -0,0 +1,312 @@
+// Package coop runs cooperative games where several players clear a single board together
+package coop
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/proxx/game"
+)
+
+// MaxPlayers limits number of players in the team who haven't left
+const MaxPlayers = 16
+
+var (
+	// ErrTeamFull is returned when MaxPlayers are playing
+	ErrTeamFull = errors.New("team is full")
+	// ErrLeft is returned on move of player who has left the game
+	ErrLeft = errors.New("player has left the game")
+)
+
+// Mode is how players take moves
+type Mode string
+
+// list of modes
+const (
+	// Free lets players move at any time, moves are applied in order they come
+	Free Mode = "free"
+	// Turns lets players move one by one in order they joined
+	Turns Mode = "turns"
+)
+
+// ParseMode parses mode name, empty name is Free
+func ParseMode(name string) (Mode, error) {
+	switch Mode(name) {
+	case "", Free:
+		return Free, nil
+	case Turns:
+		return Turns, nil
+	default:
+		return "", fmt.Errorf("unknown mode %q, expected %s or %s", name, Free, Turns)
+	}
+}
+
+// PlayerStats is contribution of the player to the game
+type PlayerStats struct {
+	Name        string `json:"name"`
+	Moves       int    `json:"moves"`
+	CellsOpened int    `json:"cellsOpened"`
+	FlagsPlaced int    `json:"flagsPlaced"`
+	Left        bool   `json:"left,omitempty"`
+}
+
+// Status is state of the team game
+type Status struct {
+	State game.State `json:"state"`
+	Mode  Mode       `json:"mode"`
+	// Turn is name of the player who moves next in Turns mode
+	Turn string `json:"turn,omitempty"`
+	// LostBy is name of the player who opened black hole
+	LostBy string `json:"lostBy,omitempty"`
+	// Players are in order they joined, including the ones who left
+	Players []PlayerStats `json:"players"`
+}
+
+// Update is sent to subscribers. board events are sent with name of the player who caused them,
+// after every move, join and leave status of the game is sent with nil Event
+type Update struct {
+	Player string
+	Event  game.Event
+	Status Status
+}
+
+// Team is a game of several players on a single board. one black hole found by anybody loses the game for everybody
+type Team struct {
+	// mu serializes moves, so every board event is attributed to the player who made the move
+	mu      sync.Mutex
+	game    *game.Game
+	board   *game.Board
+	mode    Mode
+	players []*Player
+	// turn is index of the player who moves next in Turns mode
+	turn int
+	// mover is the player whose move is being made
+	mover  *Player
+	lostBy string
+
+	updates game.Notifier
+}
+
+// Player is member of the team
+type Player struct {
+	team  *Team
+	stats PlayerStats
+}
+
+// New creates team game on the board
+func New(b *game.Board, mode Mode) (*Team, error) {
+	if mode != Free && mode != Turns {
+		return nil, fmt.Errorf("unknown mode %q", mode)
+	}
+	t := &Team{board: b, mode: mode}
+	t.game = game.NewGame(b)
+	// board events are delivered when board operation is over, while team is still locked by the move
+	b.Subscribe(t.onBoardEvent)
+
+	return t, nil
+}
+
+// Subscribe registers fn to be called on every update of the game. updates are delivered in order,
+// outside of the team lock, so fn may call methods of the team. returned function unregisters fn
+func (t *Team) Subscribe(fn func(Update)) (unsubscribe func()) {
+	return t.updates.Subscribe(func(u interface{}) { fn(u.(Update)) })
+}
+
+// Join adds player to the team. players could join game in progress. player who has left
+// comes back by joining with the same name, keeping the place in turns and the stats
+func (t *Team) Join(name string) (*Player, error) {
+	defer t.flush()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var active int
+	var returning *Player
+	for _, p := range t.players {
+		switch {
+		case p.stats.Name != name && !p.stats.Left:
+			active++
+		case p.stats.Name != name:
+		case p.stats.Left:
+			returning = p
+		default:
+			return nil, game.ErrNameTaken
+		}
+	}
+	if active >= MaxPlayers {
+		return nil, ErrTeamFull
+	}
+	p := returning
+	if p == nil {
+		p = &Player{team: t, stats: PlayerStats{Name: name}}
+		t.players = append(t.players, p)
+	}
+	p.stats.Left = false
+	if t.current() == nil {
+		t.turn = t.index(p)
+	}
+	t.changed()
+
+	return p, nil
+}
+
+// index returns position of the player in order of joining
+func (t *Team) index(p *Player) int {
+	for i, player := range t.players {
+		if player == p {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Status returns state of the game
+func (t *Team) Status() Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.status()
+}
+
+// Snapshot returns visible state of the board
+func (t *Team) Snapshot() game.Snapshot {
+	return t.board.Snapshot()
+}
+
+// Stats returns statistics of the whole team
+func (t *Team) Stats() game.Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.game.Stats()
+}
+
+func (t *Team) status() Status {
+	s := Status{State: t.game.GetState(), Mode: t.mode, LostBy: t.lostBy}
+	if p := t.current(); t.mode == Turns && p != nil && !t.game.IsFinished() {
+		s.Turn = p.stats.Name
+	}
+	for _, p := range t.players {
+		s.Players = append(s.Players, p.stats)
+	}
+
+	return s
+}
+
+// current returns player who moves next in Turns mode, nil if everybody left
+func (t *Team) current() *Player {
+	if t.turn < len(t.players) && !t.players[t.turn].stats.Left {
+		return t.players[t.turn]
+	}
+
+	return nil
+}
+
+// pass gives the turn to the next player who hasn't left
+func (t *Team) pass() {
+	for i := 1; i <= len(t.players); i++ {
+		next := (t.turn + i) % len(t.players)
+		if !t.players[next].stats.Left {
+			t.turn = next
+			return
+		}
+	}
+}
+
+// onBoardEvent attributes board event to the player making the move
+func (t *Team) onBoardEvent(e game.Event) {
+	p := t.mover
+	if p == nil {
+		return
+	}
+	switch e := e.(type) {
+	case game.CellOpenedEvent:
+		p.stats.CellsOpened++
+	case game.FlagToggledEvent:
+		if e.Flagged {
+			p.stats.FlagsPlaced++
+		}
+	case game.GameLostEvent:
+		t.lostBy = p.stats.Name
+	default:
+	}
+	t.updates.Queue(Update{Player: p.stats.Name, Event: e})
+}
+
+// changed queues status update for subscribers, team must be locked
+func (t *Team) changed() {
+	t.updates.Queue(Update{Status: t.status()})
+}
+
+// flush delivers queued updates to subscribers
+func (t *Team) flush() {
+	t.updates.Flush()
+}
+
+// Name returns name of the player
+func (p *Player) Name() string {
+	return p.stats.Name
+}
+
+// Open opens the cell
+func (p *Player) Open(row, col int) error {
+	return p.move(func(g *game.Game) error { return g.Open(row, col) })
+}
+
+// Flag puts flag on the cell or removes it
+func (p *Player) Flag(row, col int) error {
+	return p.move(func(g *game.Game) error { return g.Flag(row, col) })
+}
+
+// Chord opens neighbors of the numbered cell
+func (p *Player) Chord(row, col int) error {
+	return p.move(func(g *game.Game) error { return g.Chord(row, col) })
+}
+
+// Leave removes player from the team, the turn passes to the next player
+func (p *Player) Leave() {
+	t := p.team
+	defer t.flush()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if p.stats.Left {
+		return
+	}
+	wasCurrent := t.current() == p
+	p.stats.Left = true
+	if wasCurrent {
+		t.pass()
+	}
+	t.changed()
+}
+
+func (p *Player) move(fn func(g *game.Game) error) error {
+	t := p.team
+	defer t.flush()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch {
+	case p.stats.Left:
+		return ErrLeft
+	case t.game.IsFinished():
+		return game.ErrGameOver
+	case t.mode == Turns && t.current() != p:
+		return game.ErrNotYourTurn
+	}
+	t.mover = p
+	err := fn(t.game)
+	t.mover = nil
+	if err != nil {
+		return err
+	}
+	p.stats.Moves++
+	if t.mode == Turns {
+		t.pass()
+	}
+	t.changed()
+
+	return nil
+}
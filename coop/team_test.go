@@ -0,0 +1,182 @@
+package coop
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/proxx/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTeam creates team on 3x3 board with black hole at [0 1]
+func newTestTeam(t *testing.T, mode Mode) *Team {
+	b, err := game.NewBoardFromLayout(game.Layout{Rows: 3, Cols: 3, BlackHoles: [][]int{{0, 1}}})
+	require.NoError(t, err)
+	team, err := New(b, mode)
+	require.NoError(t, err)
+
+	return team
+}
+
+func TestTeam_Free(t *testing.T) {
+	team := newTestTeam(t, Free)
+	var updates []Update
+	team.Subscribe(func(u Update) {
+		updates = append(updates, u)
+	})
+	alice, err := team.Join("alice")
+	require.NoError(t, err)
+	bob, err := team.Join("bob")
+	require.NoError(t, err)
+	_, err = team.Join("bob")
+	assert.ErrorIs(t, err, game.ErrNameTaken)
+
+	require.NoError(t, alice.Open(2, 2))
+	require.NoError(t, bob.Flag(0, 1))
+	require.NoError(t, bob.Open(0, 0))
+	assert.ErrorIs(t, bob.Open(0, 0), game.ErrCellOpened)
+	require.NoError(t, alice.Chord(1, 1))
+	assert.ErrorIs(t, bob.Open(0, 2), game.ErrGameOver)
+
+	assert.Equal(t, Status{
+		State: "win",
+		Mode:  Free,
+		Players: []PlayerStats{
+			{Name: "alice", Moves: 2, CellsOpened: 7},
+			{Name: "bob", Moves: 2, CellsOpened: 1, FlagsPlaced: 1},
+		},
+	}, team.Status())
+	stats := team.Stats()
+	assert.True(t, stats.Won)
+	assert.Equal(t, 4, stats.Moves)
+
+	// every board event is attributed, status follows every move
+	var attributed []string
+	for _, u := range updates {
+		if u.Event == nil {
+			attributed = append(attributed, "status")
+			continue
+		}
+		attributed = append(attributed, fmt.Sprintf("%s %s", u.Player, u.Event.Kind()))
+	}
+	assert.Equal(t, []string{
+		"status", "status",
+		"alice cellOpened", "alice cellOpened", "alice cellOpened", "alice cellOpened", "alice cellOpened",
+		"alice cellOpened", "alice cascadeFinished", "status",
+		"bob flagToggled", "status",
+		"bob cellOpened", "status",
+		"alice cellOpened", "alice gameWon", "status",
+	}, attributed)
+}
+
+func TestTeam_Turns(t *testing.T) {
+	team := newTestTeam(t, Turns)
+	alice, err := team.Join("alice")
+	require.NoError(t, err)
+	bob, err := team.Join("bob")
+	require.NoError(t, err)
+	carol, err := team.Join("carol")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", team.Status().Turn)
+
+	assert.ErrorIs(t, bob.Open(2, 2), game.ErrNotYourTurn)
+	require.NoError(t, alice.Open(2, 2))
+	assert.ErrorIs(t, alice.Flag(0, 1), game.ErrNotYourTurn)
+	assert.Equal(t, "bob", team.Status().Turn)
+
+	// failed move doesn't pass the turn
+	assert.ErrorIs(t, bob.Open(2, 2), game.ErrCellOpened)
+	require.NoError(t, bob.Flag(0, 1))
+	// turn of the player who left passes to the next one
+	carol.Leave()
+	assert.ErrorIs(t, carol.Open(0, 0), ErrLeft)
+	assert.Equal(t, "alice", team.Status().Turn)
+	require.NoError(t, alice.Flag(0, 1))
+	require.NoError(t, bob.Open(0, 1))
+
+	assert.Equal(t, Status{
+		State:  "lose",
+		Mode:   Turns,
+		LostBy: "bob",
+		Players: []PlayerStats{
+			{Name: "alice", Moves: 2, CellsOpened: 6},
+			{Name: "bob", Moves: 2, FlagsPlaced: 1},
+			{Name: "carol", Left: true},
+		},
+	}, team.Status())
+	// loss is shared by everybody
+	assert.ErrorIs(t, alice.Open(0, 0), game.ErrGameOver)
+}
+
+func TestTeam_Rejoin(t *testing.T) {
+	team := newTestTeam(t, Turns)
+	alice, err := team.Join("alice")
+	require.NoError(t, err)
+	require.NoError(t, alice.Open(2, 2))
+	alice.Leave()
+	assert.Empty(t, team.Status().Turn)
+
+	// player who left comes back with the same stats and gets the turn back
+	again, err := team.Join("alice")
+	require.NoError(t, err)
+	assert.Same(t, alice, again)
+	assert.Equal(t, "alice", team.Status().Turn)
+	assert.Equal(t, []PlayerStats{{Name: "alice", Moves: 1, CellsOpened: 6}}, team.Status().Players)
+	require.NoError(t, alice.Flag(0, 1))
+	_, err = team.Join("alice")
+	assert.ErrorIs(t, err, game.ErrNameTaken)
+
+	// players who left don't take places in the team
+	for i := 0; i < MaxPlayers; i++ {
+		p, err := team.Join(fmt.Sprintf("guest%d", i))
+		require.NoError(t, err)
+		p.Leave()
+	}
+	for i := 1; i < MaxPlayers; i++ {
+		_, err := team.Join(fmt.Sprintf("player%d", i))
+		require.NoError(t, err)
+	}
+	_, err = team.Join("latecomer")
+	assert.ErrorIs(t, err, ErrTeamFull)
+	alice.Leave()
+	_, err = team.Join("latecomer")
+	assert.NoError(t, err)
+}
+
+func TestTeam_Concurrent(t *testing.T) {
+	b, err := game.NewSeededBoard(16, 40, 1)
+	require.NoError(t, err)
+	team, err := New(b, Free)
+	require.NoError(t, err)
+	var events int
+	team.Subscribe(func(u Update) {
+		if u.Event != nil {
+			events++
+		}
+	})
+
+	// players toggle flags on own rows at the same time
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		p, err := team.Join(fmt.Sprintf("player%d", i))
+		require.NoError(t, err)
+		wg.Add(1)
+		go func(p *Player, row int) {
+			defer wg.Done()
+			for col := 0; col < 16; col++ {
+				assert.NoError(t, p.Flag(row, col))
+			}
+		}(p, i)
+	}
+	wg.Wait()
+
+	status := team.Status()
+	for _, p := range status.Players {
+		assert.Equal(t, 16, p.Moves)
+		assert.Equal(t, 16, p.FlagsPlaced)
+	}
+	assert.Equal(t, 64, events)
+	assert.Equal(t, 64, b.FlagsCount())
+}
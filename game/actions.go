@@ -0,0 +1,45 @@
+package game
+
+// Game is played by commands typed to Run or by methods below, e.g. when it is played over network.
+// either way statistics are counted the same. Game is not safe for concurrent use
+
+// Open opens the cell like player typing its coordinates
+func (g *Game) Open(row, col int) error {
+	return g.do(command{kind: openCommand, row: row, col: col})
+}
+
+// Flag puts flag on the cell or removes it
+func (g *Game) Flag(row, col int) error {
+	return g.do(command{kind: flagCommand, row: row, col: col})
+}
+
+// Chord opens neighbors of the numbered cell which has all its black holes flagged
+func (g *Game) Chord(row, col int) error {
+	return g.do(command{kind: chordCommand, row: row, col: col})
+}
+
+// Undo reverts the last move
+func (g *Game) Undo() error {
+	return g.do(command{kind: undoCommand})
+}
+
+// Resign gives the game up, it is lost
+func (g *Game) Resign() error {
+	if g.IsFinished() {
+		return ErrGameOver
+	}
+	g.resigned = true
+	g.forfeit()
+
+	return nil
+}
+
+func (g *Game) do(cmd command) error {
+	// resigned game can't be continued even by undo
+	if g.resigned {
+		return ErrGameOver
+	}
+	_, err := g.handleCommand(cmd)
+
+	return err
+}
@@ -0,0 +1,43 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGame_Actions(t *testing.T) {
+	g := NewGame(newTestBoard())
+
+	require.NoError(t, g.Open(1, 1))
+	require.NoError(t, g.Flag(0, 0))
+	require.NoError(t, g.Chord(1, 1))
+	assert.Equal(t, lose, g.GetState())
+	assert.False(t, g.Stats().EndedAt.IsZero())
+
+	require.NoError(t, g.Undo())
+	assert.Equal(t, inProgress, g.GetState())
+	assert.True(t, g.Stats().EndedAt.IsZero())
+	require.NoError(t, g.Flag(0, 0))
+	require.NoError(t, g.Flag(0, 1))
+	assert.ErrorIs(t, g.Chord(0, 0), ErrCannotChord)
+	require.NoError(t, g.Chord(1, 1))
+	assert.Equal(t, win, g.GetState())
+
+	stats := g.Stats()
+	assert.Equal(t, 7, stats.Moves)
+	assert.Equal(t, 1, stats.UndosUsed)
+	assert.Equal(t, 2, stats.FlagsPlaced)
+}
+
+func TestGame_Resign(t *testing.T) {
+	g := NewGame(newTestBoard())
+	require.NoError(t, g.Open(2, 2))
+
+	require.NoError(t, g.Resign())
+	assert.Equal(t, lose, g.GetState())
+	assert.ErrorIs(t, g.Resign(), ErrGameOver)
+	assert.ErrorIs(t, g.Undo(), ErrGameOver)
+	assert.ErrorIs(t, g.Open(0, 0), ErrGameOver)
+}
@@ -1,22 +1,20 @@
 package game
 
 import (
-	"errors"
 	"fmt"
 	"math/rand"
+	"os"
+	"sync"
 	"time"
 )
 
 const (
-	keyCoordinatesFmt   = "%d_%d"
-	clickOutOfBoundsFmt = "click coordinate [%d %d] is out of board bounds %d x %d"
+	keyCoordinatesFmt = "%d_%d"
 
 	//padding when printing
 	paddingLen = "2"
-)
-
-var (
-	errCellOpened = errors.New("cell already opened")
+	// flagIcon is view of the flagged closed cell
+	flagIcon = "f"
 )
 
 // icon is cell (vertex) view for board printing. E.g. if cell is closed then "c" will be displayed when printed
@@ -33,16 +31,27 @@ func directions() [][]int {
 	return [][]int{{1, 0}, {0, 1}, {-1, 0}, {0, -1}}
 }
 
-type boardState string
+// BoardState represents state of the board
+type BoardState string
 
+// list of board states
 const (
-	blackHoled boardState = "blackHoled"
-	cleared    boardState = "cleared"
+	BoardInProgress BoardState = "inProgress"
+	BoardBlackHoled BoardState = "blackHoled"
+	BoardCleared    BoardState = "cleared"
+	// BoardForfeited is lost board given up by player or lost on time. unlike black holed board,
+	// it can't be continued by undo
+	BoardForfeited BoardState = "forfeited"
 )
 
-// Board represents board playground
+// Board represents board playground. Board is safe for concurrent use:
+// mutations are serialised and reads never observe half-finished cascade
 type Board struct {
-	boardState boardState
+	// mu guards everything below. events are delivered after it is released,
+	// so subscribers are free to call back into the board
+	mu sync.RWMutex
+
+	boardState BoardState
 	// represents relations between vertexes (cells)
 	adjacencyList map[string][]*cell
 	// represents board as two-dimensional slice. this is for printing the board
@@ -50,21 +59,49 @@ type Board struct {
 	cellList        map[string]*cell
 	sideCellsNumber int
 	// number of cells to be revealed in order to win
-	toBeRevealed     int
-	stateChangeHooks []func()
-	rows, cols       int
+	toBeRevealed int
+	rows, cols   int
+	// number of safe cells revealed so far. used as a score on boards without win state
+	revealed int
+	// blackHoles is total number of black holes, flags is number of flagged cells
+	blackHoles, flags int
+	// seed black holes were distributed with
+	seed int64
+	// chunks generates cells on demand. it is nil for boards of fixed size
+	chunks *chunkSource
+	// visible part of the board used when printing
+	viewport Viewport
+	// coordinates of the last click, nil if there were no clicks yet
+	lastClick []int
+	// events delivers board events to subscribers
+	events eventBus
+	// history of moves used for undo. current is the move being executed
+	history []*move
+	current *move
 }
 
 // NewBoard init new board as playground
 func NewBoard(sideCellsNumber, blackHolesNumber int) (*Board, error) {
+	return NewSeededBoard(sideCellsNumber, blackHolesNumber, time.Now().UnixNano())
+}
+
+// NewSeededBoard inits new board with black holes distributed by given seed.
+// the same seed, size and number of black holes always produce the same board
+func NewSeededBoard(sideCellsNumber, blackHolesNumber int, seed int64) (*Board, error) {
 	totalCellNumber := sideCellsNumber * sideCellsNumber
 	b := &Board{
 		adjacencyList:   make(map[string][]*cell),
 		sideCellsNumber: sideCellsNumber,
 		cellList:        make(map[string]*cell, totalCellNumber),
 		toBeRevealed:    totalCellNumber - blackHolesNumber,
+		blackHoles:      blackHolesNumber,
+		seed:            seed,
 		rows:            sideCellsNumber,
 		cols:            sideCellsNumber,
+		viewport: Viewport{
+			Rows: minInt(sideCellsNumber, defaultViewRows),
+			Cols: minInt(sideCellsNumber, defaultViewCols),
+		},
 	}
 
 	if totalCellNumber < blackHolesNumber {
@@ -74,83 +111,297 @@ func NewBoard(sideCellsNumber, blackHolesNumber int) (*Board, error) {
 			totalCellNumber)
 	}
 
-	blackHolesLocations := distributeBlackHoles(sideCellsNumber, blackHolesNumber)
+	// excluding this from linter check since it for game purposes it is acceptable to use it
+	//nolint: gosec
+	r := rand.New(rand.NewSource(seed))
+	blackHolesLocations := distributeBlackHoles(r, sideCellsNumber, blackHolesNumber)
 	b.board = b.generateBoard(blackHolesLocations)
 
 	b.buildGraph(b.board)
 	return b, nil
 }
 
-func (b *Board) setBoardState(boardState boardState) {
+func (b *Board) setBoardState(boardState BoardState) {
 	b.boardState = boardState
-	b.execStateChangeHooks()
 }
 
 // WinState represents winning state
 func (b *Board) WinState() bool {
-	return b.boardState == cleared
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.boardState == BoardCleared
+}
+
+// State returns current state of the board
+func (b *Board) State() BoardState {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.state()
+}
+
+func (b *Board) state() BoardState {
+	if b.boardState == "" {
+		return BoardInProgress
+	}
+
+	return b.boardState
+}
+
+// isOver checks whether board is cleared, black hole is found or game is forfeited
+func (b *Board) isOver() bool {
+	return b.boardState == BoardCleared || b.isLost()
+}
+
+// isLost checks whether black hole is found or game is forfeited
+func (b *Board) isLost() bool {
+	return b.boardState == BoardBlackHoled || b.boardState == BoardForfeited
 }
 
 // LoseState represents lose state
 func (b *Board) LoseState() bool {
-	return b.boardState == blackHoled
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.isLost()
+}
+
+// Score returns number of safe cells revealed so far
+func (b *Board) Score() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.revealed
+}
+
+// Progress returns share of safe cells revealed so far. infinite board has no progress, zero is returned
+func (b *Board) Progress() float64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	safeCells := b.rows*b.cols - b.blackHoles
+	if b.isInfinite() || safeCells == 0 {
+		return 0
+	}
+
+	return float64(b.revealed) / float64(safeCells)
+}
+
+// lockForRead locks board for reading. cells of infinite board are generated when they are read,
+// so infinite board is locked exclusively. returns function releasing the lock
+func (b *Board) lockForRead() (unlock func()) {
+	if b.isInfinite() {
+		b.mu.Lock()
+		return b.mu.Unlock
+	}
+	b.mu.RLock()
+
+	return b.mu.RUnlock
+}
+
+// Density returns share of cells with black holes
+func (b *Board) Density() float64 {
+	if b.isInfinite() {
+		return b.chunks.density
+	}
+	if b.rows*b.cols == 0 {
+		return 0
+	}
+
+	return float64(b.blackHoles) / float64(b.rows*b.cols)
+}
+
+// Seed returns seed the board was generated with
+func (b *Board) Seed() int64 {
+	if b.isInfinite() {
+		return b.chunks.seed
+	}
+
+	return b.seed
+}
+
+// isInfinite reports whether board has no fixed size
+func (b *Board) isInfinite() bool {
+	return b.chunks != nil
 }
 
 // decrementToBeRevealed decrements field toBeRevealed to track cells that is yet to be revealed to identify user win
 func (b *Board) decrementToBeRevealed() {
+	b.revealed++
+	// infinite board can't be cleared, there is only score
+	if b.isInfinite() {
+		return
+	}
 	b.toBeRevealed--
 	if b.toBeRevealed == 0 {
-		b.setBoardState(cleared)
+		b.setBoardState(BoardCleared)
 	}
 }
 
-func (b *Board) execStateChangeHooks() {
-	for _, exec := range b.stateChangeHooks {
-		exec()
-	}
+// isClickValid reports whether click is outside of the board with given dimensions
+func isClickValid(click []int, rows, cols int) bool {
+	return click[0] >= rows || click[1] >= cols ||
+		click[0] < 0 || click[1] < 0
 }
 
-// SetOnStateChangeHook set hook on every board state change
-func (b *Board) SetOnStateChangeHook(hookFn func()) {
-	b.stateChangeHooks = append(b.stateChangeHooks, hookFn)
-}
+// Forfeit loses the game without click, e.g. when player gives it up or time is over.
+// black holes are revealed and GameLostEvent is sent as for found black hole
+func (b *Board) Forfeit() error {
+	defer b.events.flush()
+	b.mu.Lock()
+	defer b.mu.Unlock()
 
-func isClickValid(click []int, sideCellsNumber int) bool {
-	return click[0] > sideCellsNumber || click[1] > sideCellsNumber ||
-		click[0] < 0 || click[1] < 0
+	if b.isOver() {
+		return ErrGameOver
+	}
+	b.setBoardState(BoardForfeited)
+	b.revealEntireBoard()
+	b.events.emit(GameLostEvent{Forfeited: true})
+
+	return nil
 }
 
 // Click executes click on the given cell. click parameter is x,y coordinates ([]int{x,y})
 func (b *Board) Click(click []int) error {
-	if isClickValid(click, b.sideCellsNumber) {
-		return fmt.Errorf(clickOutOfBoundsFmt, click[0], click[1], b.sideCellsNumber, b.sideCellsNumber)
+	result, err := b.Open(click)
+	if err != nil {
+		return err
+	}
+	if result.Outcome == OutcomeAlreadyOpen {
+		return ErrCellOpened
+	}
+
+	return nil
+}
+
+// Open executes click on the given cell like Click does and reports what was revealed.
+// click parameter is x,y coordinates ([]int{x,y})
+func (b *Board) Open(click []int) (ClickResult, error) {
+	defer b.events.flush()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.open(click)
+}
+
+func (b *Board) open(click []int) (ClickResult, error) {
+	if len(click) != 2 {
+		return ClickResult{}, ErrInvalidClick
+	}
+	if !b.isInfinite() && isClickValid(click, b.rows, b.cols) {
+		return ClickResult{}, &OutOfBoundsError{Row: click[0], Col: click[1], Rows: b.rows, Cols: b.cols}
+	}
+	if !b.reachable(click[0], click[1]) {
+		return ClickResult{}, &OutOfAreaError{Row: click[0], Col: click[1], Radius: b.chunks.radius}
+	}
+	if b.isOver() {
+		return ClickResult{}, ErrGameOver
+	}
+
+	b.lastClick = []int{click[0], click[1]}
+	if !b.window().Contains(click[0], click[1]) {
+		b.centerOn(click[0], click[1])
 	}
 
-	currentCell := b.cellList[cellIdentificationKey(click[0], click[1])]
+	currentCell := b.cellAt(click[0], click[1])
 	if currentCell.state.isOpened() {
-		return errCellOpened
+		return ClickResult{Outcome: OutcomeAlreadyOpen, State: b.state()}, nil
+	}
+	if currentCell.flagged {
+		return ClickResult{}, ErrCellFlagged
 	}
+
+	b.beginMove(MoveOpen, click)
+	defer b.commitMove()
 	if currentCell.value.isBlackHole() {
-		b.setBoardState(blackHoled)
+		b.setBoardState(BoardBlackHoled)
 		b.revealEntireBoard()
-		return nil
+		b.events.emit(GameLostEvent{Row: click[0], Col: click[1]})
+		return newClickResult(OutcomeHole, []*cell{currentCell}, b.state()), nil
+	}
+
+	opened, err := b.revealCells(cellIdentificationKey(click[0], click[1]))
+	if err != nil {
+		return ClickResult{}, err
+	}
+	outcome := OutcomeSafe
+	if len(opened) > 1 {
+		outcome = OutcomeCascade
+	}
+	result := newClickResult(outcome, opened, b.state())
+
+	for _, c := range result.Opened {
+		b.events.emit(CellOpenedEvent(c))
+	}
+	if outcome == OutcomeCascade {
+		b.events.emit(CascadeFinishedEvent{Row: click[0], Col: click[1], Opened: len(opened)})
 	}
+	if b.boardState == BoardCleared {
+		b.events.emit(GameWonEvent{Revealed: b.revealed})
+	}
+
+	return result, nil
+}
+
+// ToggleFlag puts flag on the closed cell or removes it. Flagged cell can't be opened by click or cascade.
+// returns whether cell is flagged after the call
+func (b *Board) ToggleFlag(click []int) (bool, error) {
+	defer b.events.flush()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.toggleFlag(click)
+}
+
+func (b *Board) toggleFlag(click []int) (bool, error) {
+	if len(click) != 2 {
+		return false, ErrInvalidClick
+	}
+	if !b.isInfinite() && isClickValid(click, b.rows, b.cols) {
+		return false, &OutOfBoundsError{Row: click[0], Col: click[1], Rows: b.rows, Cols: b.cols}
+	}
+	if !b.reachable(click[0], click[1]) {
+		return false, &OutOfAreaError{Row: click[0], Col: click[1], Radius: b.chunks.radius}
+	}
+	if b.isOver() {
+		return false, ErrGameOver
+	}
+
+	c := b.cellAt(click[0], click[1])
+	if !c.state.isClosed() {
+		return false, ErrCellOpened
+	}
+
+	b.beginMove(MoveFlag, click)
+	defer b.commitMove()
+	b.remember(c)
+	c.flagged = !c.flagged
+	if c.flagged {
+		b.flags++
+	} else {
+		b.flags--
+	}
+	b.events.emit(FlagToggledEvent{Row: click[0], Col: click[1], Flagged: c.flagged})
 
-	return b.revealCells(cellIdentificationKey(click[0], click[1]))
+	return c.flagged, nil
 }
 
 // revealCells uses breadth-first-search to get connected cells with void value.
 // BFS is used since it better suits for finding the closest connections (siblings/neighbors)
-// and during revealing connected neighbors this is exactly what we need
-func (b *Board) revealCells(cellID string) error {
+// and during revealing connected neighbors this is exactly what we need.
+// returns opened cells in order of revealing
+func (b *Board) revealCells(cellID string) ([]*cell, error) {
 	currentCell := b.cellList[cellID]
 
-	currentCell.state.setToOpened()
 	// if cell touches black hole - exit immediately and open just this cell
 	if currentCell.value.isTouchingBlackHoles() {
+		b.remember(currentCell)
+		currentCell.state.setToOpened()
 		b.decrementToBeRevealed()
-		return nil
+		return []*cell{currentCell}, nil
 	}
+	opened := make([]*cell, 0)
 	visited := make(map[string]struct{})
 	queue := make([]*cell, 0)
 	queue = append(queue, currentCell)
@@ -165,12 +416,24 @@ func (b *Board) revealCells(cellID string) error {
 		}
 		// visit cell
 		visited[currentNodeID] = struct{}{}
+		// cell could be opened by one of the previous clicks, it must not be counted twice.
+		// flagged cells are not opened by cascade either
+		if currentNode.state.isOpened() || currentNode.flagged {
+			continue
+		}
+		b.remember(currentNode)
 		currentNode.state.setToOpened()
+		opened = append(opened, currentNode)
 		b.decrementToBeRevealed()
 		// skip revealing neighbors since current cell is touching to the black hole
 		if !currentNode.value.isVoid() {
 			continue
 		}
+		// infinite board could have void area of any size, so cascade is cut at some point
+		if b.isInfinite() && len(visited) >= maxCascadeCells {
+			break
+		}
+		b.growAround(currentNode)
 		neighbors, ok := b.adjacencyList[currentNodeID]
 		if !ok {
 			continue
@@ -178,17 +441,18 @@ func (b *Board) revealCells(cellID string) error {
 
 		for _, neighbor := range neighbors {
 			_, ok := visited[cellIdentificationKey(neighbor.x, neighbor.y)]
-			if !ok {
+			if !ok && b.reachable(neighbor.x, neighbor.y) {
 				queue = append(queue, neighbor)
 			}
 		}
 	}
 
-	return nil
+	return opened, nil
 }
 
 func (b *Board) revealEntireBoard() {
 	for _, c := range b.cellList {
+		b.remember(c)
 		if c.value == blackHole {
 			c.state.setToBlackHoled()
 			continue
@@ -249,7 +513,7 @@ func (b *Board) addVertex(node *cell) {
 	}
 }
 
-func distributeBlackHoles(sideCount, blackHolesTargetNumber int) [][]int {
+func distributeBlackHoles(r *rand.Rand, sideCount, blackHolesTargetNumber int) [][]int {
 	//bh - black hole.
 	bhLocations := make([][]int, 0, blackHolesTargetNumber)
 
@@ -257,9 +521,6 @@ func distributeBlackHoles(sideCount, blackHolesTargetNumber int) [][]int {
 
 	var blackHolesPlaced int
 	for blackHolesPlaced < blackHolesTargetNumber {
-		// inits rand providing source to create uniformly-distributed number
-		r := rand.New(rand.NewSource(time.Now().UnixNano()))
-
 		// excluding this from linter check since it for game purposes it is acceptable to use it
 		//nolint: gosec
 		x := r.Intn(sideCount)
@@ -286,9 +547,10 @@ func cellIdentificationKey(x, y int) string {
 
 // cell represents cell data
 type cell struct {
-	state cellState
-	value cellValue
-	x, y  int
+	state   cellState
+	value   cellValue
+	flagged bool
+	x, y    int
 }
 
 // cellState represents state of cell
@@ -383,22 +645,14 @@ func (b *Board) setItems(blackHoles [][]int, board [][]*cell) [][]*cell {
 
 // Print prints current state of board
 func (b *Board) Print() {
-	for i, row := range b.board {
-		for col := range row {
-			var (
-				cellView string
-			)
-
-			switch {
-			case b.board[i][col].state.isClosed(), b.board[i][col].state.isBlackHoled():
-				cellView = stateToIconMapping()[b.board[i][col].state]
-			default:
-				cellView = fmt.Sprintf("%d", b.board[i][col].value)
-			}
+	b.Render(os.Stdout)
+}
 
-			fmt.Printf("%v %"+paddingLen+"s", cellView, "")
-			fmt.Print(" ")
-		}
-		fmt.Println()
+// cellAt returns cell with given coordinates. on infinite board cell is generated if it doesn't exist yet
+func (b *Board) cellAt(x, y int) *cell {
+	if b.isInfinite() {
+		b.materialize(x, y)
 	}
+
+	return b.cellList[cellIdentificationKey(x, y)]
 }
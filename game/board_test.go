@@ -2,6 +2,7 @@ package game
 
 import (
 	"errors"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -72,7 +73,7 @@ func TestBoard_addEdge(t *testing.T) {
 
 func TestBoard_addEdges(t *testing.T) {
 	type fields struct {
-		boardState       boardState
+		boardState       BoardState
 		adjacencyList    map[string][]*cell
 		board            [][]*cell
 		cellList         map[string]*cell
@@ -208,12 +209,40 @@ func Test_distributeBlackHoles(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			actual := distributeBlackHoles(tt.args.sideCount, tt.args.blackHolesTargetNumber)
+			r := rand.New(rand.NewSource(1))
+			actual := distributeBlackHoles(r, tt.args.sideCount, tt.args.blackHolesTargetNumber)
 			assert.Equal(t, tt.expectedLen, len(actual))
 		})
 	}
 }
 
+func TestNewSeededBoard(t *testing.T) {
+	b1, err := NewSeededBoard(8, 10, 42)
+	require.NoError(t, err)
+	b2, err := NewSeededBoard(8, 10, 42)
+	require.NoError(t, err)
+	b3, err := NewSeededBoard(8, 10, 43)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(42), b1.Seed())
+	assert.Equal(t, holesOf(b1), holesOf(b2))
+	assert.NotEqual(t, holesOf(b1), holesOf(b3))
+}
+
+// holesOf returns coordinates of black holes row by row
+func holesOf(b *Board) [][]int {
+	var holes [][]int
+	for i := range b.board {
+		for j, c := range b.board[i] {
+			if c.value.isBlackHole() {
+				holes = append(holes, []int{i, j})
+			}
+		}
+	}
+
+	return holes
+}
+
 func Test_setArtifacts(t *testing.T) {
 	type args struct {
 		blackHoleLocations [][]int
@@ -621,7 +650,7 @@ func TestBoard_revealCells(t *testing.T) {
 			b.board = b.generateBoard(tt.fields.blackHoleLocations)
 			b.buildGraph(b.board)
 
-			err := b.revealCells(tt.args.cellID)
+			_, err := b.revealCells(tt.args.cellID)
 			if tt.wantErr {
 				return
 			}
@@ -742,11 +771,65 @@ func TestBoard_Click(t *testing.T) {
 				click: []int{5, 2},
 			},
 			wantErr:     true,
-			expectedErr: errors.New("click coordinate [5 2] is out of board bounds 3 x 3"),
+			expectedErr: &OutOfBoundsError{Row: 5, Col: 2, Rows: 3, Cols: 3},
+			setupFn: func(b *Board) {
+				return
+			},
+		},
+		{
+			name: "error_click_at_board_size",
+			fields: fields{
+				cols: 3,
+				rows: 3,
+				blackHoleLocations: [][]int{
+					{0, 1},
+				},
+			},
+			args: args{
+				click: []int{3, 0},
+			},
+			wantErr:     true,
+			expectedErr: &OutOfBoundsError{Row: 3, Col: 0, Rows: 3, Cols: 3},
 			setupFn: func(b *Board) {
 				return
 			},
 		},
+		{
+			name: "error_invalid_click",
+			fields: fields{
+				cols: 3,
+				rows: 3,
+				blackHoleLocations: [][]int{
+					{0, 1},
+				},
+			},
+			args: args{
+				click: []int{1},
+			},
+			wantErr:     true,
+			expectedErr: ErrInvalidClick,
+			setupFn: func(b *Board) {
+				return
+			},
+		},
+		{
+			name: "error_game_over",
+			fields: fields{
+				cols: 3,
+				rows: 3,
+				blackHoleLocations: [][]int{
+					{0, 1},
+				},
+			},
+			args: args{
+				click: []int{2, 2},
+			},
+			wantErr:     true,
+			expectedErr: ErrGameOver,
+			setupFn: func(b *Board) {
+				b.setBoardState(BoardBlackHoled)
+			},
+		},
 		{
 			name: "error_already_opened",
 			fields: fields{
@@ -760,7 +843,7 @@ func TestBoard_Click(t *testing.T) {
 				click: []int{2, 2},
 			},
 			wantErr:     true,
-			expectedErr: errCellOpened,
+			expectedErr: ErrCellOpened,
 			setupFn: func(b *Board) {
 				// simulation of click that already took place (in some previous clicks) in order to get error of already clicked cell
 				b.board[2][2].state.setToOpened()
@@ -872,3 +955,138 @@ func TestBoard_Click(t *testing.T) {
 		})
 	}
 }
+
+func TestBoard_Open(t *testing.T) {
+	tests := []struct {
+		name    string
+		click   []int
+		setupFn func(b *Board)
+		want    ClickResult
+	}{
+		{
+			name:    "cascade",
+			click:   []int{2, 2},
+			setupFn: func(b *Board) {},
+			want: ClickResult{
+				Outcome: OutcomeCascade,
+				Opened: []OpenedCell{
+					{Row: 2, Col: 2, Value: 0},
+					{Row: 1, Col: 2, Value: 1},
+					{Row: 2, Col: 1, Value: 0},
+					{Row: 1, Col: 1, Value: 1},
+					{Row: 2, Col: 0, Value: 0},
+					{Row: 1, Col: 0, Value: 1},
+				},
+				State: BoardInProgress,
+			},
+		},
+		{
+			name:    "safe",
+			click:   []int{1, 0},
+			setupFn: func(b *Board) {},
+			want: ClickResult{
+				Outcome: OutcomeSafe,
+				Opened:  []OpenedCell{{Row: 1, Col: 0, Value: 1}},
+				State:   BoardInProgress,
+			},
+		},
+		{
+			name:    "hole",
+			click:   []int{0, 1},
+			setupFn: func(b *Board) {},
+			want: ClickResult{
+				Outcome: OutcomeHole,
+				Opened:  []OpenedCell{{Row: 0, Col: 1, Value: -1}},
+				State:   BoardBlackHoled,
+			},
+		},
+		{
+			name:  "already_open",
+			click: []int{1, 0},
+			setupFn: func(b *Board) {
+				b.board[1][0].state.setToOpened()
+			},
+			want: ClickResult{
+				Outcome: OutcomeAlreadyOpen,
+				State:   BoardInProgress,
+			},
+		},
+		{
+			name:  "cascade_does_not_count_opened_cells_twice",
+			click: []int{2, 2},
+			setupFn: func(b *Board) {
+				_, err := b.Open([]int{1, 0})
+				require.NoError(t, err)
+				_, err = b.Open([]int{0, 0})
+				require.NoError(t, err)
+				_, err = b.Open([]int{0, 2})
+				require.NoError(t, err)
+			},
+			want: ClickResult{
+				Outcome: OutcomeCascade,
+				Opened: []OpenedCell{
+					{Row: 2, Col: 2, Value: 0},
+					{Row: 1, Col: 2, Value: 1},
+					{Row: 2, Col: 1, Value: 0},
+					{Row: 1, Col: 1, Value: 1},
+					{Row: 2, Col: 0, Value: 0},
+				},
+				State: BoardCleared,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			totalCellNumber := 9
+			b := &Board{
+				adjacencyList:   make(map[string][]*cell),
+				sideCellsNumber: 3,
+				cellList:        make(map[string]*cell, totalCellNumber),
+				toBeRevealed:    totalCellNumber - 1,
+				rows:            3,
+				cols:            3,
+			}
+			b.board = b.generateBoard([][]int{{0, 1}})
+			b.buildGraph(b.board)
+			tt.setupFn(b)
+
+			actual, err := b.Open(tt.click)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, actual)
+		})
+	}
+}
+
+func TestOutOfBoundsError(t *testing.T) {
+	b, err := NewBoard(3, 1)
+	require.NoError(t, err)
+
+	err = b.Click([]int{0, 3})
+	assert.True(t, errors.Is(err, ErrOutOfBounds))
+
+	var outOfBounds *OutOfBoundsError
+	require.True(t, errors.As(err, &outOfBounds))
+	assert.Equal(t, OutOfBoundsError{Row: 0, Col: 3, Rows: 3, Cols: 3}, *outOfBounds)
+	assert.Equal(t, "click coordinate [0 3] is out of board bounds 3 x 3", err.Error())
+}
+
+func TestBoard_Forfeit(t *testing.T) {
+	b := newTestBoard()
+	var events []Event
+	b.Subscribe(func(e Event) { events = append(events, e) })
+	require.NoError(t, b.Click([]int{2, 2}))
+
+	require.NoError(t, b.Forfeit())
+	assert.Equal(t, BoardForfeited, b.State())
+	assert.True(t, b.LoseState())
+	assert.Equal(t, GameLostEvent{Forfeited: true}, events[len(events)-1])
+	hole, err := b.Cell(0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, CellBlackHole, hole.State)
+
+	// forfeited game can't be continued
+	assert.Equal(t, ErrGameOver, b.Undo())
+	assert.Equal(t, ErrGameOver, b.Forfeit())
+	assert.Equal(t, ErrGameOver, b.Click([]int{0, 0}))
+	assert.Equal(t, BoardForfeited, b.State())
+}
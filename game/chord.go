@@ -0,0 +1,93 @@
+package game
+
+import "fmt"
+
+// Chord opens all closed not flagged neighbors of the opened numbered cell when number of flags around it
+// equals to its value. every neighbor is opened like by click, so void ones start cascades.
+// if wrong cell is flagged, black hole is opened and game is lost. chord is undone as a single move
+func (b *Board) Chord(click []int) (ClickResult, error) {
+	defer b.events.flush()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.chord(click)
+}
+
+func (b *Board) chord(click []int) (ClickResult, error) {
+	if len(click) != 2 {
+		return ClickResult{}, ErrInvalidClick
+	}
+	if !b.isInfinite() && isClickValid(click, b.rows, b.cols) {
+		return ClickResult{}, &OutOfBoundsError{Row: click[0], Col: click[1], Rows: b.rows, Cols: b.cols}
+	}
+	if !b.reachable(click[0], click[1]) {
+		return ClickResult{}, &OutOfAreaError{Row: click[0], Col: click[1], Radius: b.chunks.radius}
+	}
+	if b.isOver() {
+		return ClickResult{}, ErrGameOver
+	}
+
+	c := b.cellAt(click[0], click[1])
+	if !c.state.isOpened() || !c.value.isTouchingBlackHoles() {
+		return ClickResult{}, fmt.Errorf("%w: only opened cell with number could be chorded", ErrCannotChord)
+	}
+	var flags int
+	var targets []*cell
+	for _, n := range b.neighbourCells(click[0], click[1]) {
+		switch {
+		case n.flagged:
+			flags++
+		case n.state.isClosed():
+			targets = append(targets, n)
+		}
+	}
+	if flags != int(c.value) {
+		return ClickResult{}, fmt.Errorf("%w: there are %d flags around cell with value %d", ErrCannotChord, flags, c.value)
+	}
+	b.lastClick = []int{click[0], click[1]}
+	if len(targets) == 0 {
+		return ClickResult{Outcome: OutcomeAlreadyOpen, State: b.state()}, nil
+	}
+
+	b.beginMove(MoveChord, click)
+	defer b.commitMove()
+	for _, t := range targets {
+		if !t.value.isBlackHole() {
+			continue
+		}
+		b.setBoardState(BoardBlackHoled)
+		b.revealEntireBoard()
+		b.events.emit(GameLostEvent{Row: t.x, Col: t.y})
+		return newClickResult(OutcomeHole, []*cell{t}, b.state()), nil
+	}
+
+	var opened []*cell
+	for _, t := range targets {
+		// cell could be opened by cascade started from previous neighbor
+		if t.state.isOpened() {
+			continue
+		}
+		cells, err := b.revealCells(cellIdentificationKey(t.x, t.y))
+		if err != nil {
+			return ClickResult{}, err
+		}
+		opened = append(opened, cells...)
+	}
+	outcome := OutcomeSafe
+	if len(opened) > 1 {
+		outcome = OutcomeCascade
+	}
+	result := newClickResult(outcome, opened, b.state())
+
+	for _, o := range result.Opened {
+		b.events.emit(CellOpenedEvent(o))
+	}
+	if outcome == OutcomeCascade {
+		b.events.emit(CascadeFinishedEvent{Row: click[0], Col: click[1], Opened: len(opened)})
+	}
+	if b.boardState == BoardCleared {
+		b.events.emit(GameWonEvent{Revealed: b.revealed})
+	}
+
+	return result, nil
+}
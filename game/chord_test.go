@@ -0,0 +1,129 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoard_Chord(t *testing.T) {
+	// 1 H 1
+	// 1 1 1
+	// 0 0 0
+	tests := []struct {
+		name       string
+		setupFn    func(t *testing.T, b *Board)
+		click      []int
+		want       ClickResult
+		wantErr    error
+		wantOpened int
+	}{
+		{
+			name: "opens_neighbours",
+			setupFn: func(t *testing.T, b *Board) {
+				require.NoError(t, b.Click([]int{1, 1}))
+				_, err := b.ToggleFlag([]int{0, 1})
+				require.NoError(t, err)
+			},
+			click: []int{1, 1},
+			want: ClickResult{
+				Outcome: OutcomeCascade,
+				Opened: []OpenedCell{
+					{Row: 0, Col: 0, Value: 1}, {Row: 0, Col: 2, Value: 1}, {Row: 1, Col: 0, Value: 1},
+					{Row: 1, Col: 2, Value: 1}, {Row: 2, Col: 0}, {Row: 2, Col: 1}, {Row: 2, Col: 2},
+				},
+				State: BoardCleared,
+			},
+			wantOpened: 8,
+		},
+		{
+			name: "wrong_flag_loses",
+			setupFn: func(t *testing.T, b *Board) {
+				require.NoError(t, b.Click([]int{1, 1}))
+				_, err := b.ToggleFlag([]int{0, 0})
+				require.NoError(t, err)
+			},
+			click: []int{1, 1},
+			want: ClickResult{
+				Outcome: OutcomeHole,
+				Opened:  []OpenedCell{{Row: 0, Col: 1, Value: BlackHoleValue}},
+				State:   BoardBlackHoled,
+			},
+			wantOpened: 9,
+		},
+		{
+			name: "nothing_to_open",
+			setupFn: func(t *testing.T, b *Board) {
+				require.NoError(t, b.Click([]int{2, 1}))
+				require.NoError(t, b.Click([]int{0, 0}))
+				_, err := b.ToggleFlag([]int{0, 1})
+				require.NoError(t, err)
+			},
+			click:      []int{1, 0},
+			want:       ClickResult{Outcome: OutcomeAlreadyOpen, State: BoardInProgress},
+			wantOpened: 7,
+		},
+		{
+			name: "not_enough_flags",
+			setupFn: func(t *testing.T, b *Board) {
+				require.NoError(t, b.Click([]int{1, 1}))
+			},
+			click:      []int{1, 1},
+			wantErr:    ErrCannotChord,
+			wantOpened: 1,
+		},
+		{
+			name:    "closed_cell",
+			setupFn: func(t *testing.T, b *Board) {},
+			click:   []int{1, 1},
+			wantErr: ErrCannotChord,
+		},
+		{
+			name:    "out_of_bounds",
+			setupFn: func(t *testing.T, b *Board) {},
+			click:   []int{3, 1},
+			wantErr: ErrOutOfBounds,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBoard()
+			tt.setupFn(t, b)
+
+			got, err := b.Chord(tt.click)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			var opened int
+			for _, row := range b.Snapshot().Cells {
+				for _, c := range row {
+					if c.State == CellOpened || c.State == CellBlackHole {
+						opened++
+					}
+				}
+			}
+			assert.Equal(t, tt.wantOpened, opened)
+		})
+	}
+}
+
+func TestBoard_ChordUndo(t *testing.T) {
+	b := newTestBoard()
+	require.NoError(t, b.Click([]int{1, 1}))
+	_, err := b.ToggleFlag([]int{0, 0})
+	require.NoError(t, err)
+	before := b.Snapshot()
+
+	_, err = b.Chord([]int{1, 1})
+	require.NoError(t, err)
+	assert.Equal(t, BoardBlackHoled, b.State())
+
+	require.NoError(t, b.Undo())
+	assert.Equal(t, before, b.Snapshot())
+}
@@ -0,0 +1,117 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// panStep is number of cells the board window is moved by one pan command without explicit count
+const panStep = 4
+
+var (
+	errEmptyCommand = errors.New("empty command")
+)
+
+type commandKind int
+
+const (
+	openCommand commandKind = iota
+	panCommand
+	centerCommand
+	minimapCommand
+	flagCommand
+	undoCommand
+	hintCommand
+	chordCommand
+)
+
+// command represents parsed user input
+type command struct {
+	kind commandKind
+	// coordinates of the click or offset of the pan
+	row, col int
+}
+
+// panDirections maps pan keys to the window offset
+func panDirections() map[string][]int {
+	return map[string][]int{
+		"w": {-1, 0},
+		"s": {1, 0},
+		"a": {0, -1},
+		"d": {0, 1},
+	}
+}
+
+// parseCommand parses line typed by user. Supported commands are:
+//
+//	"<row> <column>" - open cell (1-indexed)
+//	"f <row> <column>" - flag cell or remove flag
+//	"ch <row> <column>" - open neighbors of the numbered cell which has all its black holes flagged
+//	"w|a|s|d [count]" - pan board window up, left, down or right
+//	"u" - undo the last move
+//	"h" - show safe cell to open
+//	"c" - center board window on the last click
+//	"m" - show or hide minimap
+func parseCommand(line string) (command, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return command{}, errEmptyCommand
+	}
+
+	switch strings.ToLower(fields[0]) {
+	case "c":
+		return command{kind: centerCommand}, nil
+	case "m":
+		return command{kind: minimapCommand}, nil
+	case "u":
+		return command{kind: undoCommand}, nil
+	case "h":
+		return command{kind: hintCommand}, nil
+	}
+
+	direction, ok := panDirections()[strings.ToLower(fields[0])]
+	if ok {
+		step := panStep
+		if len(fields) > 1 {
+			n, err := strconv.Atoi(fields[1])
+			if err != nil || n <= 0 {
+				return command{}, fmt.Errorf("invalid pan count %q", fields[1])
+			}
+			step = n
+		}
+
+		return command{kind: panCommand, row: direction[0] * step, col: direction[1] * step}, nil
+	}
+
+	kind := openCommand
+	switch strings.ToLower(fields[0]) {
+	case "f":
+		kind = flagCommand
+		fields = fields[1:]
+	case "ch":
+		kind = chordCommand
+		fields = fields[1:]
+	}
+	if len(fields) != 2 {
+		return command{}, fmt.Errorf("unknown command %q", strings.TrimSpace(line))
+	}
+
+	return parseCoordinates(kind, fields[0], fields[1])
+}
+
+// parseCoordinates parses 1-indexed coordinates typed by user
+func parseCoordinates(kind commandKind, rowField, colField string) (command, error) {
+	row, err := strconv.Atoi(rowField)
+	if err != nil {
+		return command{}, fmt.Errorf("invalid row %q", rowField)
+	}
+	col, err := strconv.Atoi(colField)
+	if err != nil {
+		return command{}, fmt.Errorf("invalid column %q", colField)
+	}
+
+	// subtracting one since user types from 1 to n and to align with 0-indexed slices subtracting is done
+	return command{kind: kind, row: row - 1, col: col - 1}, nil
+}
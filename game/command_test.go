@@ -0,0 +1,94 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    command
+		wantErr bool
+	}{
+		{
+			name: "open",
+			line: "4 5\n",
+			want: command{kind: openCommand, row: 3, col: 4},
+		},
+		{
+			name: "flag",
+			line: "f 1 2\n",
+			want: command{kind: flagCommand, row: 0, col: 1},
+		},
+		{
+			name:    "error_flag_without_coordinates",
+			line:    "f",
+			wantErr: true,
+		},
+		{
+			name: "pan_default_step",
+			line: "w\n",
+			want: command{kind: panCommand, row: -panStep},
+		},
+		{
+			name: "pan_with_count",
+			line: "D 10",
+			want: command{kind: panCommand, col: 10},
+		},
+		{
+			name: "center",
+			line: "c\n",
+			want: command{kind: centerCommand},
+		},
+		{
+			name: "undo",
+			line: "u\n",
+			want: command{kind: undoCommand},
+		},
+		{
+			name: "chord",
+			line: "ch 2 3\n",
+			want: command{kind: chordCommand, row: 1, col: 2},
+		},
+		{
+			name: "hint",
+			line: "h\n",
+			want: command{kind: hintCommand},
+		},
+		{
+			name: "minimap",
+			line: "M",
+			want: command{kind: minimapCommand},
+		},
+		{
+			name:    "error_empty",
+			line:    "\n",
+			wantErr: true,
+		},
+		{
+			name:    "error_invalid_pan_count",
+			line:    "a -1",
+			wantErr: true,
+		},
+		{
+			name:    "error_invalid_row",
+			line:    "x 1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := parseCommand(tt.line)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, actual)
+		})
+	}
+}
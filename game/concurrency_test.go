@@ -0,0 +1,142 @@
+package game
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// these tests are meant to be run with race detector: go test -race ./game
+
+func TestBoard_concurrentMoves(t *testing.T) {
+	b, err := NewBoard(30, 60)
+	require.NoError(t, err)
+
+	var events int
+	var eventsMu sync.Mutex
+	b.Subscribe(func(Event) {
+		eventsMu.Lock()
+		events++
+		eventsMu.Unlock()
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			//nolint: gosec
+			r := rand.New(rand.NewSource(seed))
+			for j := 0; j < 200; j++ {
+				click := []int{r.Intn(30), r.Intn(30)}
+				switch r.Intn(6) {
+				case 0:
+					_, _ = b.ToggleFlag(click)
+				case 1:
+					_ = b.Undo()
+				case 2:
+					_ = b.Snapshot()
+				case 3:
+					_, _ = b.Neighbours(click[0], click[1])
+				default:
+					_, _ = b.Open(click)
+				}
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	assert.True(t, events > 0)
+}
+
+func TestBoard_Snapshot_consistentDuringCascade(t *testing.T) {
+	b, err := NewBoard(40, 40)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				assertCascadesFinished(t, b.Snapshot())
+			}
+		}()
+	}
+
+	//nolint: gosec
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		_, _ = b.Open([]int{r.Intn(40), r.Intn(40)})
+		if r.Intn(3) == 0 {
+			_ = b.Undo()
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+// assertCascadesFinished checks that no cascade is seen half-done: while game is in progress
+// every opened void cell has all its neighbors opened
+func assertCascadesFinished(t *testing.T, s Snapshot) {
+	if s.State != BoardInProgress {
+		return
+	}
+	for _, row := range s.Cells {
+		for _, c := range row {
+			if c.State != CellOpened || c.Value != 0 {
+				continue
+			}
+			for _, direction := range directions() {
+				i, j := c.Row+direction[0], c.Col+direction[1]
+				if i < 0 || i >= s.Rows || j < 0 || j >= s.Cols {
+					continue
+				}
+				if !assert.Equal(t, CellOpened, s.Cells[i][j].State) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestBoard_Subscribe_callbackIntoBoard(t *testing.T) {
+	b := newTestBoard()
+	var snapshots []Snapshot
+	b.Subscribe(func(e Event) {
+		switch e.(type) {
+		case CellOpenedEvent:
+			snapshots = append(snapshots, b.Snapshot())
+		case GameLostEvent:
+			assert.NoError(t, b.Undo())
+		}
+	})
+
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		assert.NoError(t, b.Click([]int{1, 0}))
+		assert.NoError(t, b.Click([]int{0, 1}))
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("board is deadlocked")
+	}
+	assert.Len(t, snapshots, 1)
+	// lost move is undone by subscriber
+	assert.Equal(t, BoardInProgress, b.State())
+}
@@ -0,0 +1,62 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	clickOutOfBoundsFmt = "click coordinate [%d %d] is out of board bounds %d x %d"
+	clickOutOfAreaFmt   = "click coordinate [%d %d] is out of board area, coordinates must be from %d to %d"
+)
+
+var (
+	// ErrCellOpened is returned when already opened cell is clicked
+	ErrCellOpened = errors.New("cell already opened")
+	// ErrOutOfBounds is matched by every OutOfBoundsError, so errors.Is could be used without type assertion
+	ErrOutOfBounds = errors.New("click is out of board bounds")
+	// ErrInvalidClick is returned when click doesn't consist of exactly two coordinates
+	ErrInvalidClick = errors.New("click must consist of row and column")
+	// ErrCellFlagged is returned when flagged cell is clicked. flag has to be removed first
+	ErrCellFlagged = errors.New("cell is flagged")
+	// ErrGameOver is returned on click when board is already cleared or black hole is found
+	ErrGameOver = errors.New("game is over")
+	// ErrCannotChord is returned when chorded cell is not opened number or number of flags around it differs from it
+	ErrCannotChord = errors.New("cell can't be chorded")
+	// ErrNotYourTurn is returned on move of player who waits for the turn in multiplayer game
+	ErrNotYourTurn = errors.New("it is not your turn")
+	// ErrNameTaken is returned when player joins multiplayer game with name of another player
+	ErrNameTaken = errors.New("player with this name has already joined")
+)
+
+// OutOfBoundsError is returned when click coordinates are outside of the board
+type OutOfBoundsError struct {
+	Row, Col   int
+	Rows, Cols int
+}
+
+// Error implements error interface
+func (e *OutOfBoundsError) Error() string {
+	return fmt.Sprintf(clickOutOfBoundsFmt, e.Row, e.Col, e.Rows, e.Cols)
+}
+
+// Is makes OutOfBoundsError match ErrOutOfBounds
+func (e *OutOfBoundsError) Is(target error) bool {
+	return target == ErrOutOfBounds
+}
+
+// OutOfAreaError is returned when click coordinates are outside of limited area of infinite board
+type OutOfAreaError struct {
+	Row, Col int
+	Radius   int
+}
+
+// Error implements error interface
+func (e *OutOfAreaError) Error() string {
+	return fmt.Sprintf(clickOutOfAreaFmt, e.Row, e.Col, -e.Radius, e.Radius)
+}
+
+// Is makes OutOfAreaError match ErrOutOfBounds
+func (e *OutOfAreaError) Is(target error) bool {
+	return target == ErrOutOfBounds
+}
@@ -0,0 +1,270 @@
+package game
+
+import "sync"
+
+// EventKind represents type of the board event
+type EventKind string
+
+// list of board event kinds
+const (
+	EventCellOpened      EventKind = "cellOpened"
+	EventCascadeFinished EventKind = "cascadeFinished"
+	EventFlagToggled     EventKind = "flagToggled"
+	EventGameWon         EventKind = "gameWon"
+	EventGameLost        EventKind = "gameLost"
+	EventMoveUndone      EventKind = "moveUndone"
+)
+
+// Event is something that happened on the board. Concrete type of the event is defined by its kind
+type Event interface {
+	Kind() EventKind
+}
+
+// CellOpenedEvent is sent for every safe cell opened by click or cascade
+type CellOpenedEvent struct {
+	Row, Col int
+	Value    int
+}
+
+// CascadeFinishedEvent is sent when click on void cell opened several cells. Row and Col are clicked cell
+type CascadeFinishedEvent struct {
+	Row, Col int
+	Opened   int
+}
+
+// FlagToggledEvent is sent when flag is put on the cell or removed from it
+type FlagToggledEvent struct {
+	Row, Col int
+	Flagged  bool
+}
+
+// GameWonEvent is sent when all safe cells are opened
+type GameWonEvent struct {
+	Revealed int
+}
+
+// GameLostEvent is sent when black hole is clicked or game is forfeited. Row and Col are zero for forfeit
+type GameLostEvent struct {
+	Row, Col  int
+	Forfeited bool
+}
+
+// MoveUndoneEvent is sent when the last move is reverted. Restored is number of cells returned to previous state,
+// Closed is number of safe cells opened by the move and closed again
+type MoveUndoneEvent struct {
+	Move     MoveKind
+	Row, Col int
+	Restored int
+	Closed   int
+}
+
+// Kind implements Event interface
+func (CellOpenedEvent) Kind() EventKind { return EventCellOpened }
+
+// Kind implements Event interface
+func (CascadeFinishedEvent) Kind() EventKind { return EventCascadeFinished }
+
+// Kind implements Event interface
+func (FlagToggledEvent) Kind() EventKind { return EventFlagToggled }
+
+// Kind implements Event interface
+func (GameWonEvent) Kind() EventKind { return EventGameWon }
+
+// Kind implements Event interface
+func (GameLostEvent) Kind() EventKind { return EventGameLost }
+
+// Kind implements Event interface
+func (MoveUndoneEvent) Kind() EventKind { return EventMoveUndone }
+
+// Subscribe registers fn to be called on every board event. Events are delivered in order they happened,
+// after the board operation that caused them is finished and board is unlocked, so fn may call back into the board.
+// Events of such nested calls are delivered after the current event is handled by all subscribers.
+// Only one goroutine delivers events at a time, it could be the goroutine of another board operation.
+// Returned function unregisters fn
+func (b *Board) Subscribe(fn func(Event)) (unsubscribe func()) {
+	return b.events.subscribe(fn)
+}
+
+// Events returns channel receiving board events. Board operations block while channel buffer is full,
+// so channel has to be read by another goroutine. Returned function unregisters channel and closes it
+func (b *Board) Events(buffer int) (<-chan Event, func()) {
+	var (
+		ch   = make(chan Event, buffer)
+		done = make(chan struct{})
+		// mu makes sure that channel is not closed while event is being sent to it
+		mu   sync.Mutex
+		once sync.Once
+	)
+	unsubscribe := b.events.subscribe(func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		select {
+		case <-done:
+		case ch <- e:
+		}
+	})
+
+	return ch, func() {
+		once.Do(func() {
+			unsubscribe()
+			close(done)
+			mu.Lock()
+			close(ch)
+			mu.Unlock()
+		})
+	}
+}
+
+type subscriber struct {
+	id int
+	fn func(Event)
+}
+
+// eventBus collects events during board operation and delivers them to subscribers when operation is over
+type eventBus struct {
+	mu          sync.Mutex
+	subscribers []subscriber
+	nextID      int
+	pending     []Event
+	// dispatching is set while some goroutine delivers pending events
+	dispatching bool
+}
+
+func (e *eventBus) subscribe(fn func(Event)) func() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.nextID++
+	id := e.nextID
+	e.subscribers = append(e.subscribers, subscriber{id: id, fn: fn})
+
+	return func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		for i, s := range e.subscribers {
+			if s.id == id {
+				e.subscribers = append(e.subscribers[:i:i], e.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// emit adds event to be delivered on flush
+func (e *eventBus) emit(event Event) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.pending = append(e.pending, event)
+}
+
+// flush delivers pending events to subscribers. if events are already being delivered
+// by another call (nested one or from another goroutine) that call delivers them too
+func (e *eventBus) flush() {
+	e.mu.Lock()
+	if e.dispatching {
+		e.mu.Unlock()
+		return
+	}
+	e.dispatching = true
+	e.mu.Unlock()
+
+	var finished bool
+	defer func() {
+		// subscriber panicked, so next flush has to take over delivering
+		if !finished {
+			e.mu.Lock()
+			e.dispatching = false
+			e.mu.Unlock()
+		}
+	}()
+	for {
+		event, subscribers, ok := e.next()
+		if !ok {
+			finished = true
+			return
+		}
+		for _, s := range subscribers {
+			s.fn(event)
+		}
+	}
+}
+
+// next takes the first pending event. when there are no pending events delivering is finished
+func (e *eventBus) next() (Event, []subscriber, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.pending) == 0 {
+		e.dispatching = false
+		return nil, nil, false
+	}
+	event := e.pending[0]
+	e.pending = e.pending[1:]
+
+	return event, e.subscribers, true
+}
+
+// Notifier delivers updates of multiplayer games to subscribers. updates are queued while the game is locked
+// and delivered by Flush outside of its lock in order they were queued, so subscribers may call methods
+// of the game. zero value is ready to use
+type Notifier struct {
+	mu          sync.Mutex
+	subscribers []notifierSubscriber
+	nextID      int
+	pending     []interface{}
+	// delivering serializes deliveries, so updates of two moves are not mixed
+	delivering sync.Mutex
+}
+
+type notifierSubscriber struct {
+	id int
+	fn func(interface{})
+}
+
+// Subscribe registers fn to be called on every update. returned function unregisters fn
+func (n *Notifier) Subscribe(fn func(interface{})) (unsubscribe func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.nextID++
+	id := n.nextID
+	n.subscribers = append(n.subscribers, notifierSubscriber{id: id, fn: fn})
+
+	return func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		for i, s := range n.subscribers {
+			if s.id == id {
+				n.subscribers = append(n.subscribers[:i:i], n.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Queue adds update to be delivered on Flush
+func (n *Notifier) Queue(update interface{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.pending = append(n.pending, update)
+}
+
+// Flush delivers queued updates to subscribers in order they subscribed
+func (n *Notifier) Flush() {
+	n.delivering.Lock()
+	defer n.delivering.Unlock()
+
+	n.mu.Lock()
+	pending := n.pending
+	n.pending = nil
+	subscribers := n.subscribers
+	n.mu.Unlock()
+
+	for _, u := range pending {
+		for _, s := range subscribers {
+			s.fn(u)
+		}
+	}
+}
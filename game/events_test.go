@@ -0,0 +1,133 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoard_Subscribe(t *testing.T) {
+	tests := []struct {
+		name   string
+		moves  func(t *testing.T, b *Board)
+		events []Event
+	}{
+		{
+			name: "cascade_and_win",
+			moves: func(t *testing.T, b *Board) {
+				require.NoError(t, b.Click([]int{0, 0}))
+				require.NoError(t, b.Click([]int{0, 2}))
+				require.NoError(t, b.Click([]int{2, 0}))
+			},
+			events: []Event{
+				CellOpenedEvent{Row: 0, Col: 0, Value: 1},
+				CellOpenedEvent{Row: 0, Col: 2, Value: 1},
+				CellOpenedEvent{Row: 2, Col: 0, Value: 0},
+				CellOpenedEvent{Row: 2, Col: 1, Value: 0},
+				CellOpenedEvent{Row: 1, Col: 0, Value: 1},
+				CellOpenedEvent{Row: 2, Col: 2, Value: 0},
+				CellOpenedEvent{Row: 1, Col: 1, Value: 1},
+				CellOpenedEvent{Row: 1, Col: 2, Value: 1},
+				CascadeFinishedEvent{Row: 2, Col: 0, Opened: 6},
+				GameWonEvent{Revealed: 8},
+			},
+		},
+		{
+			name: "flag_lose_and_undo",
+			moves: func(t *testing.T, b *Board) {
+				_, err := b.ToggleFlag([]int{2, 2})
+				require.NoError(t, err)
+				require.NoError(t, b.Click([]int{0, 1}))
+				require.NoError(t, b.Undo())
+			},
+			events: []Event{
+				FlagToggledEvent{Row: 2, Col: 2, Flagged: true},
+				GameLostEvent{Row: 0, Col: 1},
+				MoveUndoneEvent{Move: MoveOpen, Row: 0, Col: 1, Restored: 9},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBoard()
+			var events []Event
+			b.Subscribe(func(e Event) {
+				events = append(events, e)
+			})
+
+			tt.moves(t, b)
+			assert.Equal(t, tt.events, events)
+		})
+	}
+}
+
+func TestBoard_Subscribe_unsubscribe(t *testing.T) {
+	b := newTestBoard()
+	var first, second int
+	unsubscribe := b.Subscribe(func(Event) {
+		first++
+	})
+	b.Subscribe(func(Event) {
+		second++
+	})
+
+	require.NoError(t, b.Click([]int{0, 0}))
+	unsubscribe()
+	require.NoError(t, b.Click([]int{0, 2}))
+
+	assert.Equal(t, 1, first)
+	assert.Equal(t, 2, second)
+}
+
+func TestBoard_Subscribe_reentrant(t *testing.T) {
+	b := newTestBoard()
+	var events []Event
+	b.Subscribe(func(e Event) {
+		events = append(events, e)
+		// subscriber reacting on the event with another move gets its events after current ones
+		if opened, ok := e.(CellOpenedEvent); ok && opened.Row == 0 && opened.Col == 0 {
+			require.NoError(t, b.Click([]int{0, 2}))
+		}
+	})
+
+	require.NoError(t, b.Click([]int{0, 0}))
+	assert.Equal(t, []Event{
+		CellOpenedEvent{Row: 0, Col: 0, Value: 1},
+		CellOpenedEvent{Row: 0, Col: 2, Value: 1},
+	}, events)
+}
+
+func TestBoard_Events(t *testing.T) {
+	b := newTestBoard()
+	events, unsubscribe := b.Events(1)
+
+	require.NoError(t, b.Click([]int{0, 0}))
+	assert.Equal(t, CellOpenedEvent{Row: 0, Col: 0, Value: 1}, <-events)
+
+	unsubscribe()
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+func TestNotifier(t *testing.T) {
+	var n Notifier
+	var first, second []interface{}
+	unsubscribe := n.Subscribe(func(u interface{}) {
+		first = append(first, u)
+	})
+	n.Subscribe(func(u interface{}) {
+		second = append(second, u)
+	})
+
+	n.Queue(1)
+	n.Queue(2)
+	assert.Empty(t, first, "updates are delivered on flush only")
+	n.Flush()
+	unsubscribe()
+	n.Queue(3)
+	n.Flush()
+
+	assert.Equal(t, []interface{}{1, 2}, first)
+	assert.Equal(t, []interface{}{1, 2, 3}, second)
+}
@@ -1,8 +1,12 @@
 package game
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"io"
+	"math/rand"
+	"time"
 )
 
 //go:generate mockgen -destination=./mocks/playground.go -package=mocks github.com/proxx/game Playground
@@ -21,22 +25,76 @@ const (
 
 // Playground interface that represents methods of playground
 type Playground interface {
+	View
 	Click(click []int) error
+	ToggleFlag(click []int) (bool, error)
 	Print()
 	WinState() bool
 	LoseState() bool
-	SetOnStateChangeHook(func())
+	Undo() error
+	Hint() (row, col int, err error)
+	Subscribe(fn func(Event)) (unsubscribe func())
 }
 
 // Game represents game data
 type Game struct {
 	playground Playground
 	state      State
+	// showMinimap enables printing of minimap after the board
+	showMinimap bool
+	// moveTimeout limits time of every move, onTimeout is done when it is over
+	moveTimeout time.Duration
+	onTimeout   TimeoutAction
+	// gameClock limits time of the whole game
+	gameClock time.Duration
+	rand      *rand.Rand
+	stats     Stats
+	// recorders save results of finished games
+	recorders []Recorder
+	resigned  bool
 }
 
-// setState sets game state
+// Option configures the game
+type Option func(g *Game)
+
+// WithMinimap enables minimap printing for playgrounds that support it
+func WithMinimap() Option {
+	return func(g *Game) {
+		g.showMinimap = true
+	}
+}
+
+// Recorder saves results of finished games. returned notes are shown to player, e.g. about new records
+type Recorder interface {
+	Record(stats Stats) (notes []string, err error)
+}
+
+// WithRecorder adds recorder that saves result of the game when it is finished
+func WithRecorder(r Recorder) Option {
+	return func(g *Game) {
+		g.recorders = append(g.recorders, r)
+	}
+}
+
+// chorder is implemented by playgrounds which support chords
+type chorder interface {
+	Chord(click []int) (ClickResult, error)
+}
+
+// forfeiter is implemented by playgrounds which could be lost without click
+type forfeiter interface {
+	Forfeit() error
+}
+
+// setState sets game state. game timer stops when game is finished and continues when finished game is undone
 func (g *Game) setState(state State) {
 	g.state = state
+	switch {
+	case g.IsFinished() && g.stats.EndedAt.IsZero():
+		g.stats.EndedAt = time.Now()
+	case !g.IsFinished():
+		g.stats.EndedAt = time.Time{}
+	}
 }
 
 // GetState get game state
@@ -44,6 +102,11 @@ func (g *Game) GetState() State {
 	return g.state
 }
 
+// Won checks whether game is won
+func (g *Game) Won() bool {
+	return g.state == win
+}
+
 // IsFinished checks whether game finished
 func (g *Game) IsFinished() bool {
 	return g.state == win || g.state == lose
@@ -51,50 +114,206 @@ func (g *Game) IsFinished() bool {
 
 // NewGame inits new game.
 // accepts playground interface
-func NewGame(playground Playground) *Game {
+func NewGame(playground Playground, opts ...Option) *Game {
 	g := &Game{
 		playground: playground,
 		state:      inProgress,
+		stats:      Stats{StartedAt: time.Now()},
+		// excluding this from linter check since it for game purposes it is acceptable to use it
+		//nolint: gosec
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, opt := range opts {
+		opt(g)
 	}
 
-	g.playground.SetOnStateChangeHook(g.gameStateChangeHook)
+	g.playground.Subscribe(g.onPlaygroundEvent)
 	return g
 }
 
+// viewporter is implemented by playgrounds which visible window can be moved
+type viewporter interface {
+	Pan(dRow, dCol int)
+	CenterOnLastClick() bool
+}
+
+// minimapper is implemented by playgrounds which can print summary of the whole board
+type minimapper interface {
+	PrintMinimap()
+}
+
+// scorer is implemented by playgrounds which count score instead of having win state
+type scorer interface {
+	Score() int
+}
+
+// turner is implemented by playgrounds played by several players in turns, e.g. versus match.
+// they print their own scores and winners, so result and summary of a single player are not printed
+type turner interface {
+	Turn() string
+}
+
 // Start starts the game
-func (g *Game) Start(in *os.File) error {
-	// initial playground print
-	g.playground.Print()
-	for {
-		var coordinateX, coordinateY int
-		fmt.Print("Enter board coordinates - row and column (two digits with space):")
-		_, err := fmt.Fscan(in, &coordinateX, &coordinateY)
-		if err != nil {
-			return err
+func (g *Game) Start(in io.Reader) error {
+	return g.Run(context.Background(), in)
+}
+
+// handleCommand executes user command. returns whether it was a move, i.e. playground has changed
+func (g *Game) handleCommand(cmd command) (bool, error) {
+	var err error
+	switch cmd.kind {
+	case openCommand:
+		err = g.playground.Click([]int{cmd.row, cmd.col})
+	case flagCommand:
+		_, err = g.playground.ToggleFlag([]int{cmd.row, cmd.col})
+	case chordCommand:
+		err = g.chord(cmd.row, cmd.col)
+	case undoCommand:
+		err = g.playground.Undo()
+		if err == nil {
+			g.stats.UndosUsed++
 		}
+	case hintCommand:
+		return false, g.hint()
+	default:
+		return false, g.handleViewCommand(cmd)
+	}
+	if err != nil {
+		return false, err
+	}
+	g.stats.Moves++
+	if cmd.kind != undoCommand {
+		g.stats.Clicks++
+	}
+	if cmd.kind == openCommand || cmd.kind == chordCommand {
+		g.stats.Opens++
+	}
 
-		// subtracting one since user types from 1 to n and to align with 0-indexed slices subtracting is done
-		err = g.playground.Click([]int{coordinateX - 1, coordinateY - 1})
+	return true, nil
+}
+
+// chord opens neighbors of the numbered cell
+func (g *Game) chord(row, col int) error {
+	c, ok := g.playground.(chorder)
+	if !ok {
+		return errors.New("chord is not supported")
+	}
+	result, err := c.Chord([]int{row, col})
+	if err != nil {
+		return err
+	}
+	if result.Outcome == OutcomeAlreadyOpen {
+		return errors.New("there are no cells to open around")
+	}
+
+	return nil
+}
+
+// hint prints safe cell to open
+func (g *Game) hint() error {
+	row, col, err := g.playground.Hint()
+	if err != nil {
+		return err
+	}
+	g.stats.HintsUsed++
+	fmt.Printf("Hint: %d %d is safe to open\n", row+1, col+1)
+
+	return nil
+}
+
+// forfeit loses the game. playground which supports it is lost as well, so its state and events agree with the game
+func (g *Game) forfeit() {
+	if f, ok := g.playground.(forfeiter); ok {
+		// playground which is already over can't be forfeited, the game is lost anyway
+		_ = f.Forfeit()
+	}
+	g.setState(lose)
+}
+
+// finish prints game result and saves it
+func (g *Game) finish() {
+	if _, ok := g.playground.(turner); ok {
+		g.record()
+		return
+	}
+	fmt.Printf("You %v \n", g.GetState())
+	if s, ok := g.playground.(scorer); ok {
+		fmt.Printf("Cells cleared: %d \n", s.Score())
+	}
+	g.printSummary()
+	g.record()
+}
+
+// record passes game result to recorders. failed recording doesn't affect the game
+func (g *Game) record() {
+	stats := g.Stats()
+	for _, r := range g.recorders {
+		notes, err := r.Record(stats)
 		if err != nil {
-			fmt.Printf("Notice: %v. Repeat please.", err)
+			fmt.Printf("Notice: result is not saved: %v\n", err)
 			continue
 		}
+		for _, note := range notes {
+			fmt.Println(note)
+		}
+	}
+}
 
-		g.playground.Print()
-		if g.IsFinished() {
-			fmt.Printf("You %v \n", g.GetState())
-			return nil
+// handleViewCommand handles commands that change only the way playground is printed
+func (g *Game) handleViewCommand(cmd command) error {
+	if cmd.kind == minimapCommand {
+		if _, ok := g.playground.(minimapper); !ok {
+			return errors.New("minimap is not supported")
 		}
+		g.showMinimap = !g.showMinimap
+		return nil
+	}
+
+	v, ok := g.playground.(viewporter)
+	if !ok {
+		return errors.New("board can't be panned")
+	}
+	switch cmd.kind {
+	case panCommand:
+		v.Pan(cmd.row, cmd.col)
+	case centerCommand:
+		if !v.CenterOnLastClick() {
+			return errors.New("there were no clicks yet")
+		}
+	default:
 	}
+
+	return nil
 }
 
-// gameStateChangeHook hook that observes playground change when game is over
-func (g *Game) gameStateChangeHook() {
-	switch {
-	case g.playground.LoseState():
+// print prints playground and minimap if it is enabled
+func (g *Game) print() {
+	g.playground.Print()
+	if !g.showMinimap {
+		return
+	}
+	if m, ok := g.playground.(minimapper); ok {
+		m.PrintMinimap()
+	}
+}
+
+// onPlaygroundEvent observes playground events to find out when game is over
+func (g *Game) onPlaygroundEvent(event Event) {
+	switch e := event.(type) {
+	case CellOpenedEvent:
+		g.stats.CellsOpened++
+	case FlagToggledEvent:
+		if e.Flagged {
+			g.stats.FlagsPlaced++
+		}
+	case GameLostEvent:
 		g.setState(lose)
-	case g.playground.WinState():
+	case GameWonEvent:
 		g.setState(win)
+	case MoveUndoneEvent:
+		g.stats.CellsOpened -= e.Closed
+		// moves are possible only while game is in progress, so undone move returns game to it
+		g.setState(inProgress)
 	default:
 	}
 }
@@ -1,19 +1,22 @@
-package game
+package game_test
 
 import (
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
 	"github.com/golang/mock/gomock"
+	"github.com/proxx/game"
 	"github.com/proxx/game/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"io"
-	"os"
-	"testing"
 )
 
 func TestGame_Start(t *testing.T) {
 	type fields struct {
-		playground func(ctrl *gomock.Controller) Playground
-		state      State
+		playground func(ctrl *gomock.Controller) game.Playground
 		userInput  string
 	}
 	tests := []struct {
@@ -27,15 +30,23 @@ func TestGame_Start(t *testing.T) {
 		{
 			name: "success_lose",
 			fields: fields{
-				playground: func(ctrl *gomock.Controller) Playground {
+				playground: func(ctrl *gomock.Controller) game.Playground {
 					p := mocks.NewMockPlayground(ctrl)
+					var hook func(game.Event)
+					p.EXPECT().Subscribe(gomock.Any()).DoAndReturn(func(fn func(game.Event)) func() {
+						hook = fn
+						return func() {}
+					})
 					p.EXPECT().Print().Return().Times(2)
-					p.EXPECT().Click([]int{3, 4}).Return(nil)
+					p.EXPECT().Click([]int{3, 4}).DoAndReturn(func([]int) error {
+						// black hole is clicked, playground notifies game about it
+						hook(game.GameLostEvent{Row: 3, Col: 4})
+						return nil
+					})
 
 					return p
 				},
 				userInput: "4 5\n",
-				state:     lose,
 			},
 			setupInput: func() *os.File {
 				// simulating user input
@@ -65,10 +76,7 @@ func TestGame_Start(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
-			g := &Game{
-				playground: tt.fields.playground(ctrl),
-				state:      tt.fields.state,
-			}
+			g := game.NewGame(tt.fields.playground(ctrl))
 
 			// simulating user input
 			in := tt.setupInput()
@@ -84,3 +92,87 @@ func TestGame_Start(t *testing.T) {
 		})
 	}
 }
+
+func TestGame_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		board       func(t *testing.T) *game.Board
+		opts        []game.Option
+		cancelAfter time.Duration
+		expectedErr error
+		wantState   game.State
+		wantBoard   game.BoardState
+	}{
+		{
+			name: "cancelled",
+			board: func(t *testing.T) *game.Board {
+				b, err := game.NewBoard(3, 1)
+				require.NoError(t, err)
+				return b
+			},
+			cancelAfter: 50 * time.Millisecond,
+			expectedErr: context.Canceled,
+			wantState:   "inProgress",
+			wantBoard:   game.BoardInProgress,
+		},
+		{
+			name: "move_timeout_forfeit",
+			board: func(t *testing.T) *game.Board {
+				b, err := game.NewBoard(3, 1)
+				require.NoError(t, err)
+				return b
+			},
+			opts:      []game.Option{game.WithMoveTimeout(20*time.Millisecond, game.TimeoutForfeit)},
+			wantState: "lose",
+			wantBoard: game.BoardForfeited,
+		},
+		{
+			name: "move_timeout_random_open",
+			board: func(t *testing.T) *game.Board {
+				// board without black holes is cleared by any click
+				b, err := game.NewBoard(3, 0)
+				require.NoError(t, err)
+				return b
+			},
+			opts:      []game.Option{game.WithMoveTimeout(20*time.Millisecond, game.TimeoutRandomOpen)},
+			wantState: "win",
+			wantBoard: game.BoardCleared,
+		},
+		{
+			name: "game_clock",
+			board: func(t *testing.T) *game.Board {
+				b, err := game.NewBoard(3, 1)
+				require.NoError(t, err)
+				return b
+			},
+			opts: []game.Option{
+				game.WithGameClock(30 * time.Millisecond),
+				game.WithMoveTimeout(time.Hour, game.TimeoutForfeit),
+			},
+			wantState: "lose",
+			wantBoard: game.BoardForfeited,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.board(t)
+			g := game.NewGame(b, tt.opts...)
+
+			// player never types anything
+			in, out := io.Pipe()
+			defer out.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelAfter > 0 {
+				time.AfterFunc(tt.cancelAfter, cancel)
+			}
+
+			err := g.Run(ctx, in)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.wantState, g.GetState())
+			// board is lost together with the game, so its state and events agree with the game
+			assert.Equal(t, tt.wantBoard, b.State())
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package game
+
+import "errors"
+
+// ErrNoHint is returned by Hint when there are no closed safe cells left
+var ErrNoHint = errors.New("there is nothing to hint")
+
+// Hint returns coordinates of the closed cell without black hole. cells next to the opened ones
+// are preferred. on infinite board only visible window is searched
+func (b *Board) Hint() (row, col int, err error) {
+	unlock := b.lockForRead()
+	defer unlock()
+
+	if b.isOver() {
+		return 0, 0, ErrGameOver
+	}
+
+	top, left, rows, cols := 0, 0, b.rows, b.cols
+	if b.isInfinite() {
+		v := b.window()
+		top, left, rows, cols = v.Top, v.Left, v.Rows, v.Cols
+	}
+
+	var fallback *cell
+	for i := top; i < top+rows; i++ {
+		for j := left; j < left+cols; j++ {
+			if !b.reachable(i, j) {
+				continue
+			}
+			c := b.cellAt(i, j)
+			if !c.state.isClosed() || c.flagged || c.value.isBlackHole() {
+				continue
+			}
+			if b.touchesOpened(c) {
+				return c.x, c.y, nil
+			}
+			if fallback == nil {
+				fallback = c
+			}
+		}
+	}
+	if fallback == nil {
+		return 0, 0, ErrNoHint
+	}
+
+	return fallback.x, fallback.y, nil
+}
+
+// touchesOpened checks whether some of eight cells around c is opened
+func (b *Board) touchesOpened(c *cell) bool {
+	for i := c.x - 1; i <= c.x+1; i++ {
+		for j := c.y - 1; j <= c.y+1; j++ {
+			neighbor, ok := b.cellList[cellIdentificationKey(i, j)]
+			if ok && neighbor.state.isOpened() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
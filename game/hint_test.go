@@ -0,0 +1,75 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoard_Hint(t *testing.T) {
+	tests := []struct {
+		name             string
+		setupFn          func(t *testing.T, b *Board)
+		wantRow, wantCol int
+		wantErr          error
+	}{
+		{
+			name:    "first_safe_cell",
+			setupFn: func(t *testing.T, b *Board) {},
+			wantRow: 0,
+			wantCol: 0,
+		},
+		{
+			name: "cell_next_to_opened_is_preferred",
+			setupFn: func(t *testing.T, b *Board) {
+				b.board[2][2].state.setToOpened()
+			},
+			wantRow: 1,
+			wantCol: 1,
+		},
+		{
+			name: "flagged_cell_is_skipped",
+			setupFn: func(t *testing.T, b *Board) {
+				_, err := b.ToggleFlag([]int{0, 0})
+				require.NoError(t, err)
+			},
+			wantRow: 0,
+			wantCol: 2,
+		},
+		{
+			name: "error_game_over",
+			setupFn: func(t *testing.T, b *Board) {
+				require.NoError(t, b.Click([]int{0, 1}))
+			},
+			wantErr: ErrGameOver,
+		},
+		{
+			name: "error_nothing_to_hint",
+			setupFn: func(t *testing.T, b *Board) {
+				for _, c := range b.cellList {
+					if !c.value.isBlackHole() {
+						c.state.setToOpened()
+					}
+				}
+			},
+			wantErr: ErrNoHint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBoard()
+			tt.setupFn(t, b)
+
+			row, col, err := b.Hint()
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRow, row)
+			assert.Equal(t, tt.wantCol, col)
+		})
+	}
+}
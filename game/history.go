@@ -0,0 +1,96 @@
+package game
+
+import "errors"
+
+// ErrNothingToUndo is returned by Undo when there were no moves yet
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// MoveKind represents type of the move changing the board
+type MoveKind string
+
+// list of move kinds
+const (
+	MoveOpen MoveKind = "open"
+	MoveFlag MoveKind = "flag"
+	// MoveChord opens all not flagged neighbors of the numbered cell
+	MoveChord MoveKind = "chord"
+)
+
+// move keeps everything needed to revert changes made by one board operation
+type move struct {
+	kind     MoveKind
+	row, col int
+	changes  []cellChange
+
+	boardState                    BoardState
+	toBeRevealed, revealed, flags int
+}
+
+// cellChange keeps cell state before the move
+type cellChange struct {
+	cell    *cell
+	state   cellState
+	flagged bool
+}
+
+// Undo reverts the last move. Lost game could be continued after its last move is undone, forfeited one can't
+func (b *Board) Undo() error {
+	defer b.events.flush()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.undo()
+}
+
+func (b *Board) undo() error {
+	if b.boardState == BoardForfeited {
+		return ErrGameOver
+	}
+	if len(b.history) == 0 {
+		return ErrNothingToUndo
+	}
+
+	m := b.history[len(b.history)-1]
+	b.history = b.history[:len(b.history)-1]
+	closed := b.revealed - m.revealed
+	// restoring in reverse order so cell changed twice gets its initial state
+	for i := len(m.changes) - 1; i >= 0; i-- {
+		change := m.changes[i]
+		change.cell.state = change.state
+		change.cell.flagged = change.flagged
+	}
+	b.boardState = m.boardState
+	b.toBeRevealed, b.revealed, b.flags = m.toBeRevealed, m.revealed, m.flags
+	b.events.emit(MoveUndoneEvent{Move: m.kind, Row: m.row, Col: m.col, Restored: len(m.changes), Closed: closed})
+
+	return nil
+}
+
+// beginMove starts recording of cell changes
+func (b *Board) beginMove(kind MoveKind, click []int) {
+	b.current = &move{
+		kind:         kind,
+		row:          click[0],
+		col:          click[1],
+		boardState:   b.boardState,
+		toBeRevealed: b.toBeRevealed,
+		revealed:     b.revealed,
+		flags:        b.flags,
+	}
+}
+
+// remember saves cell state before it is changed by current move
+func (b *Board) remember(c *cell) {
+	if b.current == nil {
+		return
+	}
+	b.current.changes = append(b.current.changes, cellChange{cell: c, state: c.state, flagged: c.flagged})
+}
+
+// commitMove adds current move to the history if it changed anything
+func (b *Board) commitMove() {
+	if b.current != nil && len(b.current.changes) > 0 {
+		b.history = append(b.history, b.current)
+	}
+	b.current = nil
+}
@@ -0,0 +1,101 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoard_Undo(t *testing.T) {
+	tests := []struct {
+		name string
+		// setupFn makes moves that stay after undo
+		setupFn func(t *testing.T, b *Board)
+		// move is the move to be undone
+		move    func(t *testing.T, b *Board)
+		wantErr error
+	}{
+		{
+			name:    "error_nothing_to_undo",
+			setupFn: func(t *testing.T, b *Board) {},
+			move:    func(t *testing.T, b *Board) {},
+			wantErr: ErrNothingToUndo,
+		},
+		{
+			name: "cascade",
+			setupFn: func(t *testing.T, b *Board) {
+				require.NoError(t, b.Click([]int{1, 0}))
+			},
+			move: func(t *testing.T, b *Board) {
+				require.NoError(t, b.Click([]int{2, 2}))
+			},
+		},
+		{
+			name:    "flag",
+			setupFn: func(t *testing.T, b *Board) {},
+			move: func(t *testing.T, b *Board) {
+				_, err := b.ToggleFlag([]int{0, 1})
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "lost_game",
+			setupFn: func(t *testing.T, b *Board) {
+				require.NoError(t, b.Click([]int{0, 0}))
+			},
+			move: func(t *testing.T, b *Board) {
+				require.NoError(t, b.Click([]int{0, 1}))
+			},
+		},
+		{
+			name: "error_forfeited",
+			setupFn: func(t *testing.T, b *Board) {
+				require.NoError(t, b.Click([]int{2, 2}))
+			},
+			move: func(t *testing.T, b *Board) {
+				require.NoError(t, b.Forfeit())
+			},
+			wantErr: ErrGameOver,
+		},
+		{
+			name: "won_game",
+			setupFn: func(t *testing.T, b *Board) {
+				require.NoError(t, b.Click([]int{2, 2}))
+				require.NoError(t, b.Click([]int{0, 0}))
+			},
+			move: func(t *testing.T, b *Board) {
+				require.NoError(t, b.Click([]int{0, 2}))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBoard()
+			tt.setupFn(t, b)
+			before := b.Snapshot()
+			toBeRevealed, revealed := b.toBeRevealed, b.revealed
+
+			tt.move(t, b)
+			err := b.Undo()
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, before, b.Snapshot())
+			assert.Equal(t, toBeRevealed, b.toBeRevealed)
+			assert.Equal(t, revealed, b.revealed)
+		})
+	}
+}
+
+func TestBoard_Undo_alreadyOpenedIsNotRecorded(t *testing.T) {
+	b := newTestBoard()
+	require.NoError(t, b.Click([]int{1, 0}))
+	assert.Equal(t, ErrCellOpened, b.Click([]int{1, 0}))
+
+	require.NoError(t, b.Undo())
+	assert.Equal(t, ErrNothingToUndo, b.Undo())
+}
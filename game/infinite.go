@@ -0,0 +1,199 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+const (
+	// chunkSize is side of the square area of cells generated at once on infinite board
+	chunkSize = 16
+	// maxCascadeCells limits number of cells opened by one click on infinite board
+	maxCascadeCells = 4096
+
+	// default visible window of infinite board
+	infiniteViewRows = 16
+	infiniteViewCols = 32
+)
+
+// chunkSource generates black holes of infinite board chunk by chunk.
+// layout of every chunk depends only on seed, density and chunk position,
+// so the same seed always produces the same board regardless of exploration order
+type chunkSource struct {
+	seed    int64
+	density float64
+	// black holes layouts of chunks. layout is generated when chunk or its neighbor chunk is needed
+	layouts map[string][]bool
+	// chunks that have cells added to the board graph
+	materialized map[string]struct{}
+	// radius limits coordinates of cells which could be opened, 0 means no limit
+	radius int
+}
+
+// NewInfiniteBoard inits board without fixed size. Black holes are placed with given density
+// and are generated lazily while player explores the board. Such board has no win state
+func NewInfiniteBoard(seed int64, density float64) (*Board, error) {
+	if density <= 0 || density >= 1 {
+		return nil, fmt.Errorf("black holes density [%v] must be between 0 and 1 exclusive", density)
+	}
+
+	b := &Board{
+		adjacencyList: make(map[string][]*cell),
+		cellList:      make(map[string]*cell),
+		chunks: &chunkSource{
+			seed:         seed,
+			density:      density,
+			layouts:      make(map[string][]bool),
+			materialized: make(map[string]struct{}),
+		},
+		viewport: Viewport{
+			Top:  -infiniteViewRows / 2,
+			Left: -infiniteViewCols / 2,
+			Rows: infiniteViewRows,
+			Cols: infiniteViewCols,
+		},
+	}
+
+	return b, nil
+}
+
+// LimitArea limits infinite board to cells with both coordinates not further than radius from zero.
+// cells out of the area can't be opened or flagged and cascades stop at its edge, so the board takes bounded memory
+func (b *Board) LimitArea(radius int) error {
+	if !b.isInfinite() {
+		return errors.New("only area of infinite board could be limited")
+	}
+	if radius <= 0 {
+		return fmt.Errorf("area radius [%d] must be positive", radius)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.chunks.radius = radius
+
+	return nil
+}
+
+// reachable reports whether cell with given coordinates could be opened, cells of fixed size board are checked
+// by isClickValid
+func (b *Board) reachable(x, y int) bool {
+	if !b.isInfinite() || b.chunks.radius == 0 {
+		return true
+	}
+	r := b.chunks.radius
+
+	return x >= -r && x <= r && y >= -r && y <= r
+}
+
+// isBlackHole checks whether cell with given coordinates contains black hole
+func (s *chunkSource) isBlackHole(x, y int) bool {
+	chunkX, chunkY := chunkOf(x), chunkOf(y)
+	layout := s.layout(chunkX, chunkY)
+
+	return layout[(x-chunkX*chunkSize)*chunkSize+y-chunkY*chunkSize]
+}
+
+// layout returns black holes layout of the chunk generating it if needed
+func (s *chunkSource) layout(chunkX, chunkY int) []bool {
+	key := cellIdentificationKey(chunkX, chunkY)
+	layout, ok := s.layouts[key]
+	if ok {
+		return layout
+	}
+
+	// excluding this from linter check since it for game purposes it is acceptable to use it
+	//nolint: gosec
+	r := rand.New(rand.NewSource(chunkSeed(s.seed, chunkX, chunkY)))
+	layout = make([]bool, chunkSize*chunkSize)
+	for i := range layout {
+		layout[i] = r.Float64() < s.density
+	}
+	s.layouts[key] = layout
+
+	return layout
+}
+
+// materialize adds cells of the chunk containing given coordinates to the board graph
+func (b *Board) materialize(x, y int) {
+	chunkX, chunkY := chunkOf(x), chunkOf(y)
+	key := cellIdentificationKey(chunkX, chunkY)
+	if _, ok := b.chunks.materialized[key]; ok {
+		return
+	}
+	b.chunks.materialized[key] = struct{}{}
+
+	added := make([]*cell, 0, chunkSize*chunkSize)
+	for i := chunkX * chunkSize; i < (chunkX+1)*chunkSize; i++ {
+		for j := chunkY * chunkSize; j < (chunkY+1)*chunkSize; j++ {
+			c := &cell{
+				value: b.chunks.cellValue(i, j),
+				x:     i,
+				y:     j,
+			}
+			b.cellList[cellIdentificationKey(i, j)] = c
+			b.addVertex(c)
+			added = append(added, c)
+		}
+	}
+
+	// connecting new cells with each other and with cells of already materialized chunks
+	for _, c := range added {
+		for _, direction := range directions() {
+			neighborX, neighborY := c.x+direction[0], c.y+direction[1]
+			neighbor, ok := b.cellList[cellIdentificationKey(neighborX, neighborY)]
+			if !ok {
+				continue
+			}
+			b.addEdge(c, neighbor)
+			if chunkOf(neighborX) != chunkX || chunkOf(neighborY) != chunkY {
+				b.addEdge(neighbor, c)
+			}
+		}
+	}
+}
+
+// growAround makes sure that all neighbors of the cell are present in the board graph
+func (b *Board) growAround(c *cell) {
+	if !b.isInfinite() {
+		return
+	}
+	for _, direction := range directions() {
+		b.materialize(c.x+direction[0], c.y+direction[1])
+	}
+}
+
+// cellValue calculates value of the cell: black hole or number of black holes around
+func (s *chunkSource) cellValue(x, y int) cellValue {
+	if s.isBlackHole(x, y) {
+		return blackHole
+	}
+
+	var value cellValue
+	for i := x - 1; i <= x+1; i++ {
+		for j := y - 1; j <= y+1; j++ {
+			if s.isBlackHole(i, j) {
+				value++
+			}
+		}
+	}
+
+	return value
+}
+
+// chunkOf returns chunk index of the coordinate. division is rounded down so negative coordinates work too
+func chunkOf(coordinate int) int {
+	if coordinate < 0 {
+		return (coordinate+1)/chunkSize - 1
+	}
+
+	return coordinate / chunkSize
+}
+
+// chunkSeed mixes board seed with chunk position (splitmix64 finalizer) so neighbor chunks are not correlated
+func chunkSeed(seed int64, chunkX, chunkY int) int64 {
+	z := uint64(seed) ^ uint64(int64(chunkX))*0x9e3779b97f4a7c15 ^ uint64(int64(chunkY))*0xc2b2ae3d27d4eb4f
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+
+	return int64(z ^ (z >> 31))
+}
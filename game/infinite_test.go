@@ -0,0 +1,150 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInfiniteBoard(t *testing.T) {
+	tests := []struct {
+		name    string
+		density float64
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			density: 0.2,
+		},
+		{
+			name:    "error_zero_density",
+			density: 0,
+			wantErr: true,
+		},
+		{
+			name:    "error_full_density",
+			density: 1,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := NewInfiniteBoard(1, tt.density)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.False(t, b.WinState())
+			assert.False(t, b.LoseState())
+		})
+	}
+}
+
+func TestChunkSource_cellValue(t *testing.T) {
+	s := &chunkSource{
+		seed:    42,
+		density: 0.3,
+		layouts: make(map[string][]bool),
+	}
+	other := &chunkSource{
+		seed:    42,
+		density: 0.3,
+		layouts: make(map[string][]bool),
+	}
+
+	// generating other source in reverse order
+	for x := chunkSize + 1; x >= -chunkSize-2; x-- {
+		for y := chunkSize + 1; y >= -chunkSize-2; y-- {
+			other.isBlackHole(x, y)
+		}
+	}
+
+	// crossing chunk borders including negative coordinates
+	for x := -chunkSize - 1; x < chunkSize+1; x++ {
+		for y := -chunkSize - 1; y < chunkSize+1; y++ {
+			// the same seed gives the same layout regardless of generation order
+			assert.Equal(t, other.isBlackHole(x, y), s.isBlackHole(x, y))
+
+			if s.isBlackHole(x, y) {
+				assert.Equal(t, blackHole, s.cellValue(x, y))
+				continue
+			}
+			var expected cellValue
+			for i := x - 1; i <= x+1; i++ {
+				for j := y - 1; j <= y+1; j++ {
+					if s.isBlackHole(i, j) {
+						expected++
+					}
+				}
+			}
+			assert.Equal(t, expected, s.cellValue(x, y))
+		}
+	}
+}
+
+func TestBoard_Click_infinite(t *testing.T) {
+	b, err := NewInfiniteBoard(7, 0.15)
+	require.NoError(t, err)
+
+	// looking for void cell near the origin
+	var start *cell
+	for x := 0; x < chunkSize && start == nil; x++ {
+		for y := 0; y < chunkSize; y++ {
+			c := b.cellAt(x, y)
+			if c.value.isVoid() {
+				start = c
+				break
+			}
+		}
+	}
+	require.NotNil(t, start)
+
+	err = b.Click([]int{start.x, start.y})
+	require.NoError(t, err)
+
+	opened := 0
+	for _, c := range b.cellList {
+		assert.False(t, c.state.isOpened() && c.value.isBlackHole())
+		if c.state.isOpened() {
+			opened++
+		}
+	}
+	assert.Equal(t, opened, b.Score())
+	assert.True(t, b.Score() > 1)
+	assert.False(t, b.WinState())
+	assert.False(t, b.LoseState())
+}
+
+func TestBoard_LimitArea(t *testing.T) {
+	fixed, err := NewSeededBoard(3, 1, 1)
+	require.NoError(t, err)
+	assert.Error(t, fixed.LimitArea(5))
+
+	b, err := NewInfiniteBoard(7, 0.01)
+	require.NoError(t, err)
+	assert.Error(t, b.LimitArea(0))
+	require.NoError(t, b.LimitArea(5))
+
+	// black holes are rare, so cascade spreads until the edge of the area
+	require.NoError(t, b.Click([]int{0, 0}))
+	for _, c := range b.cellList {
+		if c.state.isOpened() {
+			assert.True(t, c.x >= -5 && c.x <= 5 && c.y >= -5 && c.y <= 5, "cell [%d %d] is opened", c.x, c.y)
+		}
+	}
+	assert.Greater(t, b.Score(), 1)
+	// only chunks covering the area are materialized
+	assert.LessOrEqual(t, len(b.chunks.materialized), 4)
+
+	err = b.Click([]int{6, 0})
+	assert.ErrorIs(t, err, ErrOutOfBounds)
+	assert.EqualError(t, err, "click coordinate [6 0] is out of board area, coordinates must be from -5 to 5")
+	_, err = b.ToggleFlag([]int{0, -6})
+	assert.ErrorIs(t, err, ErrOutOfBounds)
+	_, err = b.Chord([]int{-6, 0})
+	assert.ErrorIs(t, err, ErrOutOfBounds)
+	assert.LessOrEqual(t, len(b.chunks.materialized), 4)
+}
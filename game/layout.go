@@ -0,0 +1,68 @@
+package game
+
+import "fmt"
+
+// Layout represents positions of black holes on board of fixed size
+type Layout struct {
+	Rows, Cols int
+	// BlackHoles contains row and column of every black hole, row by row
+	BlackHoles [][]int
+}
+
+// Layout returns positions of black holes. layout of infinite board is never known entirely,
+// so ErrInfiniteBoard is returned for it
+func (b *Board) Layout() (Layout, error) {
+	if b.isInfinite() {
+		return Layout{}, ErrInfiniteBoard
+	}
+	// values of cells never change after board creation, so lock is not needed
+
+	l := Layout{Rows: b.rows, Cols: b.cols, BlackHoles: make([][]int, 0, b.blackHoles)}
+	for i := 0; i < b.rows; i++ {
+		for j := 0; j < b.cols; j++ {
+			if b.board[i][j].value.isBlackHole() {
+				l.BlackHoles = append(l.BlackHoles, []int{i, j})
+			}
+		}
+	}
+
+	return l, nil
+}
+
+// NewBoardFromLayout inits new board with black holes at given positions.
+// it is used to play the same board again, e.g. when game is replayed
+func NewBoardFromLayout(l Layout) (*Board, error) {
+	if l.Rows <= 0 || l.Cols <= 0 {
+		return nil, fmt.Errorf("board size [%d x %d] must be positive", l.Rows, l.Cols)
+	}
+	occupied := make(map[string]struct{}, len(l.BlackHoles))
+	for _, h := range l.BlackHoles {
+		if len(h) != 2 || isClickValid(h, l.Rows, l.Cols) {
+			return nil, fmt.Errorf("black hole %v is out of the board [%d x %d]", h, l.Rows, l.Cols)
+		}
+		key := cellIdentificationKey(h[0], h[1])
+		if _, ok := occupied[key]; ok {
+			return nil, fmt.Errorf("black hole %v is duplicated", h)
+		}
+		occupied[key] = struct{}{}
+	}
+
+	totalCellNumber := l.Rows * l.Cols
+	b := &Board{
+		adjacencyList:   make(map[string][]*cell),
+		sideCellsNumber: l.Rows,
+		cellList:        make(map[string]*cell, totalCellNumber),
+		toBeRevealed:    totalCellNumber - len(l.BlackHoles),
+		blackHoles:      len(l.BlackHoles),
+		rows:            l.Rows,
+		cols:            l.Cols,
+		viewport: Viewport{
+			Rows: minInt(l.Rows, defaultViewRows),
+			Cols: minInt(l.Cols, defaultViewCols),
+		},
+	}
+	b.board = b.generateBoard(l.BlackHoles)
+	b.buildGraph(b.board)
+
+	return b, nil
+}
@@ -0,0 +1,73 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoard_Layout(t *testing.T) {
+	b, err := NewSeededBoard(9, 10, 5)
+	require.NoError(t, err)
+
+	l, err := b.Layout()
+	require.NoError(t, err)
+	assert.Equal(t, 9, l.Rows)
+	assert.Equal(t, holesOf(b), l.BlackHoles)
+
+	copied, err := NewBoardFromLayout(l)
+	require.NoError(t, err)
+	assert.Equal(t, b.Snapshot(), copied.Snapshot())
+	copiedMetrics, err := copied.Metrics()
+	require.NoError(t, err)
+	metrics, err := b.Metrics()
+	require.NoError(t, err)
+	assert.Equal(t, metrics, copiedMetrics)
+
+	infinite, err := NewInfiniteBoard(1, 0.1)
+	require.NoError(t, err)
+	_, err = infinite.Layout()
+	assert.ErrorIs(t, err, ErrInfiniteBoard)
+}
+
+func TestNewBoardFromLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		layout  Layout
+		wantErr string
+	}{
+		{
+			name:   "rectangular",
+			layout: Layout{Rows: 2, Cols: 3, BlackHoles: [][]int{{1, 2}}},
+		},
+		{
+			name:    "empty",
+			layout:  Layout{},
+			wantErr: "board size [0 x 0] must be positive",
+		},
+		{
+			name:    "out_of_board",
+			layout:  Layout{Rows: 2, Cols: 2, BlackHoles: [][]int{{2, 0}}},
+			wantErr: "black hole [2 0] is out of the board [2 x 2]",
+		},
+		{
+			name:    "duplicated",
+			layout:  Layout{Rows: 2, Cols: 2, BlackHoles: [][]int{{1, 1}, {1, 1}}},
+			wantErr: "black hole [1 1] is duplicated",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := NewBoardFromLayout(tt.layout)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			got, err := b.Layout()
+			require.NoError(t, err)
+			assert.Equal(t, tt.layout, got)
+		})
+	}
+}
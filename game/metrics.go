@@ -0,0 +1,110 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInfiniteBoard is returned for operations that need the whole board to be known
+var ErrInfiniteBoard = errors.New("operation is not supported by infinite board")
+
+// Metrics represents difficulty of the board layout. they are computed the same way cascade opens cells:
+// void cells are connected with their north, south, west and east neighbours
+type Metrics struct {
+	// BBBV (3BV) is minimum number of clicks needed to clear the board
+	BBBV int
+	// Openings is number of connected areas of void cells, each of them is cleared by a single click
+	Openings int
+	// IsolatedCells is number of numbered cells not opened by any opening
+	IsolatedCells int
+	// ZeroCells is total number of void cells
+	ZeroCells int
+}
+
+// Metrics computes difficulty metrics of the board layout. layout of infinite board is never known entirely,
+// so ErrInfiniteBoard is returned for it
+func (b *Board) Metrics() (Metrics, error) {
+	if b.isInfinite() {
+		return Metrics{}, ErrInfiniteBoard
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var m Metrics
+	// cells opened by clicks on void cells
+	opened := make(map[string]struct{})
+	for i := 0; i < b.rows; i++ {
+		for j := 0; j < b.cols; j++ {
+			c := b.board[i][j]
+			if !c.value.isVoid() {
+				continue
+			}
+			m.ZeroCells++
+			key := cellIdentificationKey(i, j)
+			if _, ok := opened[key]; ok {
+				continue
+			}
+			m.Openings++
+			b.markOpening(key, opened)
+		}
+	}
+
+	for i := 0; i < b.rows; i++ {
+		for j := 0; j < b.cols; j++ {
+			if !b.board[i][j].value.isTouchingBlackHoles() {
+				continue
+			}
+			if _, ok := opened[cellIdentificationKey(i, j)]; !ok {
+				m.IsolatedCells++
+			}
+		}
+	}
+	m.BBBV = m.Openings + m.IsolatedCells
+
+	return m, nil
+}
+
+// markOpening marks cells that are opened by click on the void cell with given key, like revealCells does
+func (b *Board) markOpening(key string, opened map[string]struct{}) {
+	queue := []string{key}
+	opened[key] = struct{}{}
+	for len(queue) > 0 {
+		currentKey := queue[0]
+		queue = queue[1:]
+		if !b.cellList[currentKey].value.isVoid() {
+			continue
+		}
+		for _, neighbor := range b.adjacencyList[currentKey] {
+			neighborKey := cellIdentificationKey(neighbor.x, neighbor.y)
+			if _, ok := opened[neighborKey]; ok {
+				continue
+			}
+			opened[neighborKey] = struct{}{}
+			queue = append(queue, neighborKey)
+		}
+	}
+}
+
+// RenderLayout writes the whole board with all cells revealed to w. game state is not changed
+func (b *Board) RenderLayout(w io.Writer) error {
+	if b.isInfinite() {
+		return ErrInfiniteBoard
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for i := 0; i < b.rows; i++ {
+		for j := 0; j < b.cols; j++ {
+			cellView := fmt.Sprintf("%d", b.board[i][j].value)
+			if b.board[i][j].value.isBlackHole() {
+				cellView = stateToIconMapping()[blackHoledState]
+			}
+			fmt.Fprintf(w, "%v %"+paddingLen+"s", cellView, "")
+			fmt.Fprint(w, " ")
+		}
+		fmt.Fprintln(w)
+	}
+
+	return nil
+}
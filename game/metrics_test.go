@@ -0,0 +1,80 @@
+package game
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoard_Metrics(t *testing.T) {
+	tests := []struct {
+		name       string
+		size       int
+		blackHoles [][]int
+		want       Metrics
+	}{
+		{
+			name: "no_black_holes",
+			size: 3,
+			want: Metrics{BBBV: 1, Openings: 1, ZeroCells: 9},
+		},
+		{
+			name:       "corner_black_hole",
+			size:       3,
+			blackHoles: [][]int{{0, 1}},
+			// 1 H 1
+			// 1 1 1
+			// 0 0 0
+			// cascade is four-directional, so corner cells are not reached from zeros
+			want: Metrics{BBBV: 3, Openings: 1, IsolatedCells: 2, ZeroCells: 3},
+		},
+		{
+			name:       "two_openings",
+			size:       5,
+			blackHoles: [][]int{{0, 2}, {1, 2}, {2, 2}, {3, 2}, {4, 2}},
+			want:       Metrics{BBBV: 2, Openings: 2, ZeroCells: 10},
+		},
+		{
+			name:       "no_zero_cells",
+			size:       2,
+			blackHoles: [][]int{{0, 0}},
+			want:       Metrics{BBBV: 3, IsolatedCells: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Board{
+				adjacencyList: make(map[string][]*cell),
+				cellList:      make(map[string]*cell),
+				rows:          tt.size,
+				cols:          tt.size,
+			}
+			b.board = b.generateBoard(tt.blackHoles)
+			b.buildGraph(b.board)
+
+			got, err := b.Metrics()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBoard_MetricsInfinite(t *testing.T) {
+	b, err := NewInfiniteBoard(1, 0.1)
+	require.NoError(t, err)
+
+	_, err = b.Metrics()
+	assert.ErrorIs(t, err, ErrInfiniteBoard)
+	assert.ErrorIs(t, b.RenderLayout(&bytes.Buffer{}), ErrInfiniteBoard)
+}
+
+func TestBoard_RenderLayout(t *testing.T) {
+	b := newTestBoard()
+
+	var buf bytes.Buffer
+	require.NoError(t, b.RenderLayout(&buf))
+	assert.Equal(t, "1    H    1    \n1    1    1    \n0    0    0    \n", buf.String())
+	assert.Equal(t, BoardInProgress, b.State())
+}
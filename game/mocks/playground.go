@@ -6,6 +6,7 @@ package mocks
 
 import (
 	gomock "github.com/golang/mock/gomock"
+	game "github.com/proxx/game"
 	reflect "reflect"
 )
 
@@ -32,6 +33,21 @@ func (m *MockPlayground) EXPECT() *MockPlaygroundMockRecorder {
 	return m.recorder
 }
 
+// Cell mocks base method
+func (m *MockPlayground) Cell(arg0, arg1 int) (game.CellView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cell", arg0, arg1)
+	ret0, _ := ret[0].(game.CellView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cell indicates an expected call of Cell
+func (mr *MockPlaygroundMockRecorder) Cell(arg0 interface{}, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cell", reflect.TypeOf((*MockPlayground)(nil).Cell), arg0, arg1)
+}
+
 // Click mocks base method
 func (m *MockPlayground) Click(arg0 []int) error {
 	m.ctrl.T.Helper()
@@ -46,6 +62,51 @@ func (mr *MockPlaygroundMockRecorder) Click(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Click", reflect.TypeOf((*MockPlayground)(nil).Click), arg0)
 }
 
+// Dimensions mocks base method
+func (m *MockPlayground) Dimensions() (int, int) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dimensions")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	return ret0, ret1
+}
+
+// Dimensions indicates an expected call of Dimensions
+func (mr *MockPlaygroundMockRecorder) Dimensions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dimensions", reflect.TypeOf((*MockPlayground)(nil).Dimensions))
+}
+
+// FlagsCount mocks base method
+func (m *MockPlayground) FlagsCount() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlagsCount")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// FlagsCount indicates an expected call of FlagsCount
+func (mr *MockPlaygroundMockRecorder) FlagsCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlagsCount", reflect.TypeOf((*MockPlayground)(nil).FlagsCount))
+}
+
+// Hint mocks base method
+func (m *MockPlayground) Hint() (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Hint")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Hint indicates an expected call of Hint
+func (mr *MockPlaygroundMockRecorder) Hint() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Hint", reflect.TypeOf((*MockPlayground)(nil).Hint))
+}
+
 // LoseState mocks base method
 func (m *MockPlayground) LoseState() bool {
 	m.ctrl.T.Helper()
@@ -60,6 +121,21 @@ func (mr *MockPlaygroundMockRecorder) LoseState() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoseState", reflect.TypeOf((*MockPlayground)(nil).LoseState))
 }
 
+// Neighbours mocks base method
+func (m *MockPlayground) Neighbours(arg0, arg1 int) ([]game.CellView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Neighbours", arg0, arg1)
+	ret0, _ := ret[0].([]game.CellView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Neighbours indicates an expected call of Neighbours
+func (mr *MockPlaygroundMockRecorder) Neighbours(arg0 interface{}, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Neighbours", reflect.TypeOf((*MockPlayground)(nil).Neighbours), arg0, arg1)
+}
+
 // Print mocks base method
 func (m *MockPlayground) Print() {
 	m.ctrl.T.Helper()
@@ -84,16 +160,75 @@ func (mr *MockPlaygroundMockRecorder) PrintStateless() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrintStateless", reflect.TypeOf((*MockPlayground)(nil).PrintStateless))
 }
 
-// SetOnStateChangeHook mocks base method
-func (m *MockPlayground) SetOnStateChangeHook(arg0 func()) {
+// RemainingHoles mocks base method
+func (m *MockPlayground) RemainingHoles() int {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "SetOnStateChangeHook", arg0)
+	ret := m.ctrl.Call(m, "RemainingHoles")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// RemainingHoles indicates an expected call of RemainingHoles
+func (mr *MockPlaygroundMockRecorder) RemainingHoles() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemainingHoles", reflect.TypeOf((*MockPlayground)(nil).RemainingHoles))
+}
+
+// Snapshot mocks base method
+func (m *MockPlayground) Snapshot() game.Snapshot {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot")
+	ret0, _ := ret[0].(game.Snapshot)
+	return ret0
+}
+
+// Snapshot indicates an expected call of Snapshot
+func (mr *MockPlaygroundMockRecorder) Snapshot() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockPlayground)(nil).Snapshot))
+}
+
+// Subscribe mocks base method
+func (m *MockPlayground) Subscribe(arg0 func(game.Event)) func() {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", arg0)
+	ret0, _ := ret[0].(func())
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe
+func (mr *MockPlaygroundMockRecorder) Subscribe(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockPlayground)(nil).Subscribe), arg0)
+}
+
+// ToggleFlag mocks base method
+func (m *MockPlayground) ToggleFlag(arg0 []int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ToggleFlag", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ToggleFlag indicates an expected call of ToggleFlag
+func (mr *MockPlaygroundMockRecorder) ToggleFlag(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToggleFlag", reflect.TypeOf((*MockPlayground)(nil).ToggleFlag), arg0)
+}
+
+// Undo mocks base method
+func (m *MockPlayground) Undo() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Undo")
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// SetOnStateChangeHook indicates an expected call of SetOnStateChangeHook
-func (mr *MockPlaygroundMockRecorder) SetOnStateChangeHook(arg0 interface{}) *gomock.Call {
+// Undo indicates an expected call of Undo
+func (mr *MockPlaygroundMockRecorder) Undo() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOnStateChangeHook", reflect.TypeOf((*MockPlayground)(nil).SetOnStateChangeHook), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undo", reflect.TypeOf((*MockPlayground)(nil).Undo))
 }
 
 // WinState mocks base method
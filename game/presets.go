@@ -0,0 +1,64 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Preset represents standard board configuration
+type Preset struct {
+	Name       string
+	Size       int
+	BlackHoles int
+}
+
+// list of presets. boards are square, so number of black holes keeps density of the classic presets
+var presets = []Preset{
+	{Name: "beginner", Size: 9, BlackHoles: 10},
+	{Name: "intermediate", Size: 16, BlackHoles: 40},
+	{Name: "expert", Size: 22, BlackHoles: 99},
+}
+
+// Presets returns list of standard board configurations from the easiest one
+func Presets() []Preset {
+	return append([]Preset(nil), presets...)
+}
+
+// PresetByName finds preset by its name
+func PresetByName(name string) (Preset, error) {
+	for _, p := range presets {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	names := make([]string, 0, len(presets))
+	for _, p := range presets {
+		names = append(names, p.Name)
+	}
+
+	return Preset{}, fmt.Errorf("unknown preset %q, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// ProfileName returns name results of the board are grouped by: name of the matching preset
+// or size and number of black holes of custom board
+func ProfileName(size, blackHoles int) string {
+	for _, p := range presets {
+		if p.Size == size && p.BlackHoles == blackHoles {
+			return p.Name
+		}
+	}
+
+	return fmt.Sprintf("custom-%dx%d-%d", size, size, blackHoles)
+}
+
+// LayoutProfile returns profile name of the board with given layout
+func LayoutProfile(l Layout) string {
+	if l.Rows == l.Cols {
+		return ProfileName(l.Rows, len(l.BlackHoles))
+	}
+
+	return fmt.Sprintf("custom-%dx%d-%d", l.Rows, l.Cols, len(l.BlackHoles))
+}
+
+// InfiniteProfile is profile name of infinite boards
+const InfiniteProfile = "infinite"
@@ -0,0 +1,22 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresetByName(t *testing.T) {
+	p, err := PresetByName("intermediate")
+	require.NoError(t, err)
+	assert.Equal(t, Preset{Name: "intermediate", Size: 16, BlackHoles: 40}, p)
+
+	_, err = PresetByName("nightmare")
+	assert.EqualError(t, err, `unknown preset "nightmare", expected one of beginner, intermediate, expert`)
+}
+
+func TestProfileName(t *testing.T) {
+	assert.Equal(t, "beginner", ProfileName(9, 10))
+	assert.Equal(t, "custom-9x9-11", ProfileName(9, 11))
+}
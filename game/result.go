@@ -0,0 +1,51 @@
+package game
+
+// Outcome describes what click on the board has done
+type Outcome string
+
+// list of click outcomes
+const (
+	// OutcomeSafe means that single cell touching black holes was opened
+	OutcomeSafe Outcome = "safe"
+	// OutcomeCascade means that void cell was clicked and its neighbors were opened too
+	OutcomeCascade Outcome = "cascade"
+	// OutcomeHole means that black hole was clicked and game is lost
+	OutcomeHole Outcome = "hole"
+	// OutcomeAlreadyOpen means that clicked cell was opened before and nothing has changed
+	OutcomeAlreadyOpen Outcome = "alreadyOpen"
+)
+
+// OpenedCell represents cell opened by the click
+type OpenedCell struct {
+	Row, Col int
+	// Value is number of black holes around the cell or -1 if cell is black hole itself
+	Value int
+}
+
+// ClickResult represents result of the click
+type ClickResult struct {
+	Outcome Outcome
+	// Opened contains cells opened by the click in breadth-first-search order.
+	// when black hole is clicked it contains only that black hole, though entire board is revealed
+	Opened []OpenedCell
+	// State is board state after the click
+	State BoardState
+}
+
+// CascadeSize returns number of cells opened by the click
+func (r ClickResult) CascadeSize() int {
+	return len(r.Opened)
+}
+
+func newClickResult(outcome Outcome, opened []*cell, state BoardState) ClickResult {
+	result := ClickResult{
+		Outcome: outcome,
+		Opened:  make([]OpenedCell, 0, len(opened)),
+		State:   state,
+	}
+	for _, c := range opened {
+		result.Opened = append(result.Opened, OpenedCell{Row: c.x, Col: c.y, Value: int(c.value)})
+	}
+
+	return result
+}
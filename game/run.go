@@ -0,0 +1,209 @@
+package game
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// TimeoutAction is what game does when player doesn't make a move in time
+type TimeoutAction string
+
+// list of timeout actions
+const (
+	// TimeoutForfeit loses the game
+	TimeoutForfeit TimeoutAction = "forfeit"
+	// TimeoutRandomOpen opens random closed cell instead of player
+	TimeoutRandomOpen TimeoutAction = "random"
+)
+
+const prompt = "Enter board coordinates - row and column (two digits with space), " +
+	"f or ch with coordinates to flag or chord, u to undo, h for hint, w/a/s/d to pan, c to center, m for minimap:"
+
+var errNoClosedCells = errors.New("there are no closed cells to open")
+
+// prompter is implemented by playgrounds which accept only part of the commands, their prompt lists them
+type prompter interface {
+	Prompt() string
+}
+
+// WithMoveTimeout limits time of every move. when time is over action is done instead of player
+func WithMoveTimeout(timeout time.Duration, action TimeoutAction) Option {
+	return func(g *Game) {
+		g.moveTimeout = timeout
+		g.onTimeout = action
+	}
+}
+
+// WithGameClock limits time of the whole game. game is lost when time is over
+func WithGameClock(clock time.Duration) Option {
+	return func(g *Game) {
+		g.gameClock = clock
+	}
+}
+
+// ParseTimeoutAction converts name of timeout action to TimeoutAction
+func ParseTimeoutAction(name string) (TimeoutAction, error) {
+	switch action := TimeoutAction(name); action {
+	case TimeoutForfeit, TimeoutRandomOpen:
+		return action, nil
+	default:
+		return "", fmt.Errorf("unknown timeout action %q, expected %q or %q", name, TimeoutForfeit, TimeoutRandomOpen)
+	}
+}
+
+// inputLine is line read from player input
+type inputLine struct {
+	text string
+	err  error
+}
+
+// Run runs the game reading player commands from in until game is over or ctx is done.
+// Context error is returned when game is interrupted. Reading from in is done by separate goroutine,
+// which stops as soon as Run returns and pending read is finished, so in should be closed
+// (or provide data) for it to exit when game is interrupted
+func (g *Game) Run(ctx context.Context, in io.Reader) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	lines := readLines(ctx, in)
+
+	var clock <-chan time.Time
+	if g.gameClock > 0 {
+		clockTimer := time.NewTimer(g.gameClock)
+		defer clockTimer.Stop()
+		clock = clockTimer.C
+	}
+	var moveTimer *time.Timer
+	var moveDeadline <-chan time.Time
+	if g.moveTimeout > 0 {
+		moveTimer = time.NewTimer(g.moveTimeout)
+		defer moveTimer.Stop()
+		moveDeadline = moveTimer.C
+	}
+
+	g.stats.StartedAt = time.Now()
+	// initial playground print
+	g.print()
+	for {
+		fmt.Print(g.prompt())
+		var moved bool
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clock:
+			fmt.Println("\nNotice: game time is over.")
+			g.forfeit()
+		case <-moveDeadline:
+			fmt.Println("\nNotice: move time is over.")
+			moved = g.timeout()
+		case line, ok := <-lines:
+			if !ok {
+				// input is over after the last line without line break
+				return io.EOF
+			}
+			if line.err != nil && (line.err != io.EOF || strings.TrimSpace(line.text) == "") {
+				return line.err
+			}
+
+			cmd, err := parseCommand(line.text)
+			if err == nil {
+				moved, err = g.handleCommand(cmd)
+			}
+			if err != nil {
+				fmt.Printf("Notice: %v. Repeat please.", err)
+				continue
+			}
+		}
+
+		if moved && moveTimer != nil {
+			resetTimer(moveTimer, g.moveTimeout)
+		}
+		g.print()
+		if g.IsFinished() {
+			g.finish()
+			return nil
+		}
+	}
+}
+
+// prompt returns prompt of the playground or the one listing all commands
+func (g *Game) prompt() string {
+	if p, ok := g.playground.(prompter); ok {
+		return p.Prompt()
+	}
+
+	return prompt
+}
+
+// timeout does timeout action. returns whether playground has changed
+func (g *Game) timeout() bool {
+	if g.onTimeout == TimeoutRandomOpen {
+		err := g.openRandomCell()
+		if err == nil {
+			g.stats.Moves++
+			return true
+		}
+		fmt.Printf("Notice: %v.", err)
+	}
+	g.forfeit()
+
+	return false
+}
+
+// openRandomCell clicks random closed cell of the playground
+func (g *Game) openRandomCell() error {
+	snapshot := g.playground.Snapshot()
+	closed := make([]CellView, 0)
+	for _, row := range snapshot.Cells {
+		for _, c := range row {
+			if c.State == CellClosed {
+				closed = append(closed, c)
+			}
+		}
+	}
+	if len(closed) == 0 {
+		return errNoClosedCells
+	}
+
+	c := closed[g.rand.Intn(len(closed))]
+	fmt.Printf("Opening %d %d\n", c.Row+1, c.Col+1)
+
+	return g.playground.Click([]int{c.Row, c.Col})
+}
+
+// readLines reads lines from in until reading fails or ctx is done
+func readLines(ctx context.Context, in io.Reader) <-chan inputLine {
+	lines := make(chan inputLine)
+	go func() {
+		defer close(lines)
+		reader := bufio.NewReader(in)
+		for {
+			text, err := reader.ReadString('\n')
+			select {
+			case lines <- inputLine{text: text, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return lines
+}
+
+// resetTimer restarts timer draining its channel if it has fired already
+func resetTimer(t *time.Timer, d time.Duration) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+	t.Reset(d)
+}
@@ -0,0 +1,150 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// score rules
+const (
+	pointsPerCell = 10
+	winBonus      = 500
+	hintPenalty   = 50
+	undoPenalty   = 100
+	// difficultyPerDensity makes board with 10% of black holes twice as hard as empty one
+	difficultyPerDensity = 10
+)
+
+// Stats represents game statistics
+type Stats struct {
+	StartedAt, EndedAt time.Time
+	// Moves is number of opens, flags and undos made
+	Moves int
+	// Clicks is number of opens and flags made by player
+	Clicks int
+	// Opens is number of opens and chords made by player, the moves which could hit black hole
+	Opens int
+	// CellsOpened is number of safe cells opened by moves which weren't undone
+	CellsOpened int
+	// Cleared is number of safe cells opened when game finished, as reported by playground
+	Cleared     int
+	FlagsPlaced int
+	HintsUsed   int
+	UndosUsed   int
+	Won         bool
+	// Progress is share of safe cells cleared, zero if playground doesn't report it
+	Progress float64
+	// Seed is seed of the board, zero if playground doesn't report it
+	Seed       int64
+	Difficulty float64
+	Score      int
+	// BBBV is 3BV of the board and Efficiency is 3BV divided by clicks.
+	// they are known only when game on board of fixed size is finished
+	BBBV       int
+	Efficiency float64
+}
+
+// Duration returns game time. time of game in progress is counted till now
+func (s Stats) Duration() time.Duration {
+	if s.StartedAt.IsZero() {
+		return 0
+	}
+	if s.EndedAt.IsZero() {
+		return time.Since(s.StartedAt)
+	}
+
+	return s.EndedAt.Sub(s.StartedAt)
+}
+
+// densityRater is implemented by playgrounds which know share of black holes among cells
+type densityRater interface {
+	Density() float64
+}
+
+// progresser is implemented by playgrounds which know how much of them is cleared
+type progresser interface {
+	Progress() float64
+}
+
+// seeder is implemented by playgrounds generated from seed
+type seeder interface {
+	Seed() int64
+}
+
+// metricser is implemented by playgrounds which can rate their layout
+type metricser interface {
+	Metrics() (Metrics, error)
+}
+
+// Stats returns game statistics
+func (g *Game) Stats() Stats {
+	stats := g.stats
+	stats.Won = g.state == win
+	stats.Difficulty = g.difficulty()
+	stats.Cleared = stats.CellsOpened
+	if s, ok := g.playground.(scorer); ok {
+		stats.Cleared = s.Score()
+	}
+	stats.Score = stats.ComputeScore()
+	if s, ok := g.playground.(seeder); ok {
+		stats.Seed = s.Seed()
+	}
+	if p, ok := g.playground.(progresser); ok {
+		stats.Progress = p.Progress()
+	}
+	if m, ok := g.playground.(metricser); ok && g.IsFinished() {
+		if metrics, err := m.Metrics(); err == nil {
+			stats.BBBV = metrics.BBBV
+			if stats.Clicks > 0 {
+				stats.Efficiency = float64(metrics.BBBV) / float64(stats.Clicks)
+			}
+		}
+	}
+
+	return stats
+}
+
+// difficulty rates the board, boards which don't know their density are rated as empty ones
+func (g *Game) difficulty() float64 {
+	d, ok := g.playground.(densityRater)
+	if !ok {
+		return 1
+	}
+
+	return Difficulty(d.Density())
+}
+
+// Difficulty rates board with given share of black holes. empty board has difficulty 1,
+// every percent of black holes adds 0.1
+func Difficulty(density float64) float64 {
+	return 1 + difficultyPerDensity*density
+}
+
+// ComputeScore calculates points for the game: every cleared cell costs pointsPerCell, won game gets bonus
+// and extra points for every second left from par time (a second per cleared cell).
+// all that is multiplied by difficulty, then hints and undos are subtracted
+func (s Stats) ComputeScore() int {
+	points := float64(s.Cleared * pointsPerCell)
+	if s.Won {
+		points += winBonus
+		secondsLeft := float64(s.Cleared) - s.Duration().Seconds()
+		points += math.Max(0, secondsLeft) * pointsPerCell
+	}
+	points *= s.Difficulty
+	points -= float64(s.HintsUsed*hintPenalty + s.UndosUsed*undoPenalty)
+
+	return int(math.Max(0, math.Round(points)))
+}
+
+// printSummary prints game statistics
+func (g *Game) printSummary() {
+	stats := g.Stats()
+	fmt.Printf("Time: %v, moves: %d, cells opened: %d, flags placed: %d, hints: %d, undos: %d\n",
+		stats.Duration().Round(time.Millisecond), stats.Moves, stats.CellsOpened, stats.FlagsPlaced,
+		stats.HintsUsed, stats.UndosUsed)
+	if stats.BBBV > 0 {
+		fmt.Printf("3BV: %d, clicks: %d, efficiency: %.0f%%\n", stats.BBBV, stats.Clicks, stats.Efficiency*100)
+	}
+	fmt.Printf("Score: %d\n", stats.Score)
+}
@@ -0,0 +1,89 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGame_Stats(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands []command
+		want     Stats
+	}{
+		{
+			name: "won_with_hint_and_undo",
+			commands: []command{
+				{kind: flagCommand, row: 0, col: 1},
+				{kind: hintCommand},
+				{kind: openCommand, row: 0, col: 0},
+				{kind: undoCommand},
+				{kind: openCommand, row: 2, col: 2},
+				{kind: openCommand, row: 0, col: 0},
+				{kind: openCommand, row: 0, col: 2},
+			},
+			want: Stats{
+				Moves:       6,
+				Clicks:      5,
+				Opens:       4,
+				CellsOpened: 8,
+				Cleared:     8,
+				FlagsPlaced: 1,
+				HintsUsed:   1,
+				UndosUsed:   1,
+				Won:         true,
+				Progress:    1,
+				Difficulty:  1 + 10.0/9,
+				// (8 cells * 10 + 500 win bonus + 8 seconds left * 10) * difficulty - 50 - 100
+				Score:      1243,
+				BBBV:       3,
+				Efficiency: 0.6,
+			},
+		},
+		{
+			name: "failed_moves_are_not_counted",
+			commands: []command{
+				{kind: undoCommand},
+				{kind: openCommand, row: 5, col: 5},
+				{kind: openCommand, row: 0, col: 0},
+			},
+			want: Stats{
+				Moves:       1,
+				Clicks:      1,
+				Opens:       1,
+				CellsOpened: 1,
+				Cleared:     1,
+				Progress:    0.125,
+				Difficulty:  1 + 10.0/9,
+				Score:       21,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGame(newTestBoard())
+			g.stats.StartedAt = time.Now()
+			for _, cmd := range tt.commands {
+				_, _ = g.handleCommand(cmd)
+			}
+			g.stats.EndedAt = g.stats.StartedAt
+
+			got := g.Stats()
+			require.Equal(t, time.Duration(0), got.Duration())
+			got.StartedAt, got.EndedAt = time.Time{}, time.Time{}
+			assert.InDelta(t, tt.want.Difficulty, got.Difficulty, 1e-9)
+			got.Difficulty = tt.want.Difficulty
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStats_Duration(t *testing.T) {
+	start := time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Duration(0), Stats{}.Duration())
+	assert.Equal(t, time.Minute, Stats{StartedAt: start, EndedAt: start.Add(time.Minute)}.Duration())
+	assert.True(t, Stats{StartedAt: time.Now().Add(-time.Second)}.Duration() >= time.Second)
+}
@@ -0,0 +1,203 @@
+package game
+
+// BlackHoleValue is value of the revealed black hole cell
+const BlackHoleValue = int(blackHole)
+
+// CellState represents state of the cell visible to the player
+type CellState string
+
+// list of visible cell states
+const (
+	CellClosed  CellState = "closed"
+	CellFlagged CellState = "flagged"
+	CellOpened  CellState = "opened"
+	// CellBlackHole is black hole revealed when game is lost
+	CellBlackHole CellState = "blackHole"
+)
+
+// CellView represents cell as player sees it. Value is known only for opened cells and
+// revealed black holes, for closed and flagged cells it is always zero
+type CellView struct {
+	Row, Col int
+	State    CellState
+	Value    int
+}
+
+// Snapshot represents visible state of the board at some moment
+type Snapshot struct {
+	// Top and Left are coordinates of the first cell. they are not zero only for infinite board
+	Top, Left  int
+	Rows, Cols int
+	State      BoardState
+	// RemainingHoles is number of black holes minus number of flags
+	RemainingHoles int
+	Flags          int
+	// Cells has Rows rows with Cols cells in each
+	Cells [][]CellView
+}
+
+// Symbol returns one character representation of the cell: # closed, F flagged, 0-8 opened and * black hole
+func (c CellView) Symbol() byte {
+	switch c.State {
+	case CellOpened:
+		return byte('0' + c.Value)
+	case CellBlackHole:
+		return '*'
+	case CellFlagged:
+		return 'F'
+	default:
+		return '#'
+	}
+}
+
+// Symbols returns rows of cell symbols, see CellView.Symbol
+func (s Snapshot) Symbols() []string {
+	rows := make([]string, 0, len(s.Cells))
+	for _, row := range s.Cells {
+		line := make([]byte, 0, len(row))
+		for _, c := range row {
+			line = append(line, c.Symbol())
+		}
+		rows = append(rows, string(line))
+	}
+
+	return rows
+}
+
+// View represents read-only access to the board. It never exposes values of closed cells
+type View interface {
+	Dimensions() (rows, cols int)
+	Cell(row, col int) (CellView, error)
+	Neighbours(row, col int) ([]CellView, error)
+	RemainingHoles() int
+	FlagsCount() int
+	Snapshot() Snapshot
+}
+
+// Dimensions returns number of rows and columns of the board. infinite board has no bounds, so zeros are returned
+func (b *Board) Dimensions() (rows, cols int) {
+	// dimensions never change after board creation, so lock is not needed
+	return b.rows, b.cols
+}
+
+// Cell returns visible state of the cell with given coordinates
+func (b *Board) Cell(row, col int) (CellView, error) {
+	if !b.isInfinite() && isClickValid([]int{row, col}, b.rows, b.cols) {
+		return CellView{}, &OutOfBoundsError{Row: row, Col: col, Rows: b.rows, Cols: b.cols}
+	}
+	unlock := b.lockForRead()
+	defer unlock()
+
+	return b.cellAt(row, col).view(), nil
+}
+
+// Neighbours returns up to eight cells surrounding given one, row by row.
+// these are the cells counted in the value of the cell
+func (b *Board) Neighbours(row, col int) ([]CellView, error) {
+	if !b.isInfinite() && isClickValid([]int{row, col}, b.rows, b.cols) {
+		return nil, &OutOfBoundsError{Row: row, Col: col, Rows: b.rows, Cols: b.cols}
+	}
+	unlock := b.lockForRead()
+	defer unlock()
+
+	cells := b.neighbourCells(row, col)
+	neighbours := make([]CellView, 0, len(cells))
+	for _, c := range cells {
+		neighbours = append(neighbours, c.view())
+	}
+
+	return neighbours, nil
+}
+
+// neighbourCells returns up to eight cells surrounding given one, row by row
+func (b *Board) neighbourCells(row, col int) []*cell {
+	neighbours := make([]*cell, 0, 8)
+	for i := row - 1; i <= row+1; i++ {
+		for j := col - 1; j <= col+1; j++ {
+			if i == row && j == col {
+				continue
+			}
+			if !b.isInfinite() && isClickValid([]int{i, j}, b.rows, b.cols) {
+				continue
+			}
+			neighbours = append(neighbours, b.cellAt(i, j))
+		}
+	}
+
+	return neighbours
+}
+
+// RemainingHoles returns number of black holes not flagged yet. it could be negative if there are
+// more flags than black holes. number of black holes on infinite board is unknown, so -1 is returned
+func (b *Board) RemainingHoles() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.remainingHoles()
+}
+
+func (b *Board) remainingHoles() int {
+	if b.isInfinite() {
+		return -1
+	}
+
+	return b.blackHoles - b.flags
+}
+
+// FlagsCount returns number of flagged cells
+func (b *Board) FlagsCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.flags
+}
+
+// Snapshot returns visible state of the whole board. for infinite board only visible window is included
+func (b *Board) Snapshot() Snapshot {
+	unlock := b.lockForRead()
+	defer unlock()
+
+	top, left, rows, cols := 0, 0, b.rows, b.cols
+	if b.isInfinite() {
+		v := b.window()
+		top, left, rows, cols = v.Top, v.Left, v.Rows, v.Cols
+	}
+
+	snapshot := Snapshot{
+		Top:            top,
+		Left:           left,
+		Rows:           rows,
+		Cols:           cols,
+		State:          b.state(),
+		RemainingHoles: b.remainingHoles(),
+		Flags:          b.flags,
+		Cells:          make([][]CellView, rows),
+	}
+	for i := 0; i < rows; i++ {
+		snapshot.Cells[i] = make([]CellView, cols)
+		for j := 0; j < cols; j++ {
+			snapshot.Cells[i][j] = b.cellAt(top+i, left+j).view()
+		}
+	}
+
+	return snapshot
+}
+
+// view converts cell to its visible representation hiding values of closed cells
+func (c *cell) view() CellView {
+	v := CellView{Row: c.x, Col: c.y}
+	switch {
+	case c.state.isOpened():
+		v.State = CellOpened
+		v.Value = int(c.value)
+	case c.state.isBlackHoled():
+		v.State = CellBlackHole
+		v.Value = BlackHoleValue
+	case c.flagged:
+		v.State = CellFlagged
+	default:
+		v.State = CellClosed
+	}
+
+	return v
+}
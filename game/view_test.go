@@ -0,0 +1,162 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestBoard creates 3x3 board with black hole at [0 1]
+func newTestBoard() *Board {
+	b := &Board{
+		adjacencyList:   make(map[string][]*cell),
+		sideCellsNumber: 3,
+		cellList:        make(map[string]*cell, 9),
+		toBeRevealed:    8,
+		blackHoles:      1,
+		rows:            3,
+		cols:            3,
+	}
+	b.board = b.generateBoard([][]int{{0, 1}})
+	b.buildGraph(b.board)
+
+	return b
+}
+
+func TestBoard_Cell(t *testing.T) {
+	tests := []struct {
+		name     string
+		row, col int
+		setupFn  func(t *testing.T, b *Board)
+		want     CellView
+		wantErr  error
+	}{
+		{
+			name:    "closed_cell_value_is_hidden",
+			row:     0,
+			col:     0,
+			setupFn: func(t *testing.T, b *Board) {},
+			want:    CellView{Row: 0, Col: 0, State: CellClosed},
+		},
+		{
+			name: "opened_cell",
+			row:  1,
+			col:  1,
+			setupFn: func(t *testing.T, b *Board) {
+				require.NoError(t, b.Click([]int{1, 1}))
+			},
+			want: CellView{Row: 1, Col: 1, State: CellOpened, Value: 1},
+		},
+		{
+			name: "flagged_cell",
+			row:  0,
+			col:  1,
+			setupFn: func(t *testing.T, b *Board) {
+				_, err := b.ToggleFlag([]int{0, 1})
+				require.NoError(t, err)
+			},
+			want: CellView{Row: 0, Col: 1, State: CellFlagged},
+		},
+		{
+			name: "revealed_black_hole",
+			row:  0,
+			col:  1,
+			setupFn: func(t *testing.T, b *Board) {
+				require.NoError(t, b.Click([]int{0, 1}))
+			},
+			want: CellView{Row: 0, Col: 1, State: CellBlackHole, Value: BlackHoleValue},
+		},
+		{
+			name:    "error_out_of_bounds",
+			row:     3,
+			col:     0,
+			setupFn: func(t *testing.T, b *Board) {},
+			wantErr: &OutOfBoundsError{Row: 3, Col: 0, Rows: 3, Cols: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBoard()
+			tt.setupFn(t, b)
+
+			actual, err := b.Cell(tt.row, tt.col)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, actual)
+		})
+	}
+}
+
+func TestBoard_Neighbours(t *testing.T) {
+	b := newTestBoard()
+
+	corner, err := b.Neighbours(0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []CellView{
+		{Row: 0, Col: 1, State: CellClosed},
+		{Row: 1, Col: 0, State: CellClosed},
+		{Row: 1, Col: 1, State: CellClosed},
+	}, corner)
+
+	center, err := b.Neighbours(1, 1)
+	require.NoError(t, err)
+	assert.Len(t, center, 8)
+}
+
+func TestBoard_ToggleFlag(t *testing.T) {
+	b := newTestBoard()
+
+	flagged, err := b.ToggleFlag([]int{0, 1})
+	require.NoError(t, err)
+	assert.True(t, flagged)
+	assert.Equal(t, 1, b.FlagsCount())
+	assert.Equal(t, 0, b.RemainingHoles())
+
+	// flagged cell can't be opened
+	assert.Equal(t, ErrCellFlagged, b.Click([]int{0, 1}))
+
+	flagged, err = b.ToggleFlag([]int{0, 1})
+	require.NoError(t, err)
+	assert.False(t, flagged)
+	assert.Equal(t, 0, b.FlagsCount())
+	assert.Equal(t, 1, b.RemainingHoles())
+
+	require.NoError(t, b.Click([]int{1, 1}))
+	_, err = b.ToggleFlag([]int{1, 1})
+	assert.Equal(t, ErrCellOpened, err)
+}
+
+func TestBoard_Snapshot(t *testing.T) {
+	b := newTestBoard()
+	_, err := b.ToggleFlag([]int{2, 2})
+	require.NoError(t, err)
+	require.NoError(t, b.Click([]int{1, 0}))
+
+	snapshot := b.Snapshot()
+	assert.Equal(t, 3, snapshot.Rows)
+	assert.Equal(t, 3, snapshot.Cols)
+	assert.Equal(t, BoardInProgress, snapshot.State)
+	assert.Equal(t, 1, snapshot.Flags)
+	assert.Equal(t, 0, snapshot.RemainingHoles)
+	require.Len(t, snapshot.Cells, 3)
+	for _, row := range snapshot.Cells {
+		require.Len(t, row, 3)
+		for _, c := range row {
+			switch {
+			case c.Row == 1 && c.Col == 0:
+				assert.Equal(t, CellView{Row: 1, Col: 0, State: CellOpened, Value: 1}, c)
+			case c.Row == 2 && c.Col == 2:
+				assert.Equal(t, CellView{Row: 2, Col: 2, State: CellFlagged}, c)
+			default:
+				assert.Equal(t, CellView{Row: c.Row, Col: c.Col, State: CellClosed}, c)
+			}
+		}
+	}
+	assert.Equal(t, []string{"###", "1##", "##F"}, snapshot.Symbols())
+	assert.Equal(t, byte('*'), CellView{State: CellBlackHole, Value: BlackHoleValue}.Symbol())
+}
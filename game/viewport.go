@@ -0,0 +1,284 @@
+package game
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// default maximum visible part of board of fixed size. bigger boards are panned
+	defaultViewRows = 24
+	defaultViewCols = 32
+
+	// maximum size of minimap in symbols
+	minimapMaxRows = 12
+	minimapMaxCols = 24
+)
+
+// minimap symbols. each symbol summarises block of cells
+const (
+	minimapUnexplored = "#"
+	minimapPartly     = "+"
+	minimapExplored   = "."
+	minimapBlackHole  = "H"
+	minimapViewport   = "@"
+)
+
+// Viewport represents visible part of the board
+type Viewport struct {
+	Top, Left  int
+	Rows, Cols int
+}
+
+// Contains checks whether cell with given coordinates is visible
+func (v Viewport) Contains(row, col int) bool {
+	return v.Top <= row && row < v.Top+v.Rows &&
+		v.Left <= col && col < v.Left+v.Cols
+}
+
+// Viewport returns visible part of the board
+func (b *Board) Viewport() Viewport {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.window()
+}
+
+// SetViewSize changes size of visible part of the board. on board of fixed size
+// viewport can't be bigger than the board itself
+func (b *Board) SetViewSize(rows, cols int) error {
+	if rows <= 0 || cols <= 0 {
+		return fmt.Errorf("viewport size [%d x %d] must be positive", rows, cols)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	v := b.window()
+	v.Rows, v.Cols = rows, cols
+	b.setViewport(v)
+
+	return nil
+}
+
+// Pan moves visible window of the board by given number of rows and columns
+func (b *Board) Pan(dRow, dCol int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	v := b.window()
+	v.Top += dRow
+	v.Left += dCol
+	b.setViewport(v)
+}
+
+// CenterOn moves visible window of the board so given cell is in the middle of it
+func (b *Board) CenterOn(row, col int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.centerOn(row, col)
+}
+
+func (b *Board) centerOn(row, col int) {
+	v := b.window()
+	v.Top = row - v.Rows/2
+	v.Left = col - v.Cols/2
+	b.setViewport(v)
+}
+
+// CenterOnLastClick moves visible window to the last clicked cell.
+// returns false if there were no clicks yet
+func (b *Board) CenterOnLastClick() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.lastClick == nil {
+		return false
+	}
+	b.centerOn(b.lastClick[0], b.lastClick[1])
+
+	return true
+}
+
+// setViewport sets viewport keeping it inside of board of fixed size
+func (b *Board) setViewport(v Viewport) {
+	if !b.isInfinite() {
+		v.Rows = clamp(v.Rows, 1, b.rows)
+		v.Cols = clamp(v.Cols, 1, b.cols)
+		v.Top = clamp(v.Top, 0, b.rows-v.Rows)
+		v.Left = clamp(v.Left, 0, b.cols-v.Cols)
+	}
+	b.viewport = v
+}
+
+// window returns visible part of the board. by default board of fixed size is visible entirely
+func (b *Board) window() Viewport {
+	if b.viewport.Rows == 0 || b.viewport.Cols == 0 {
+		return Viewport{Rows: b.rows, Cols: b.cols}
+	}
+
+	return b.viewport
+}
+
+// isPartlyVisible checks whether some cells of the board are out of the visible window
+func (b *Board) isPartlyVisible() bool {
+	v := b.window()
+	return b.isInfinite() || v.Rows < b.rows || v.Cols < b.cols
+}
+
+// Render writes visible part of the board to w
+func (b *Board) Render(w io.Writer) {
+	unlock := b.lockForRead()
+	defer unlock()
+
+	v := b.window()
+	if b.isPartlyVisible() {
+		// user types coordinates starting from 1, so they are printed the same way
+		fmt.Fprintf(w, "rows %d..%d, columns %d..%d", v.Top+1, v.Top+v.Rows, v.Left+1, v.Left+v.Cols)
+		if b.isInfinite() {
+			fmt.Fprintf(w, ", cleared %d", b.revealed)
+		}
+		fmt.Fprintln(w)
+	}
+	for i := v.Top; i < v.Top+v.Rows; i++ {
+		for j := v.Left; j < v.Left+v.Cols; j++ {
+			var (
+				cellView string
+			)
+
+			c := b.cellAt(i, j)
+			switch {
+			case c.state.isClosed() && c.flagged:
+				cellView = flagIcon
+			case c.state.isClosed(), c.state.isBlackHoled():
+				cellView = stateToIconMapping()[c.state]
+			default:
+				cellView = fmt.Sprintf("%d", c.value)
+			}
+
+			fmt.Fprintf(w, "%v %"+paddingLen+"s", cellView, "")
+			fmt.Fprint(w, " ")
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// PrintMinimap prints summary of the whole board with the visible window marked
+func (b *Board) PrintMinimap() {
+	b.RenderMinimap(os.Stdout)
+}
+
+// RenderMinimap writes minimap to w. every symbol of minimap summarises square block of cells:
+// "#" - nothing is explored, "+" - partly explored, "." - explored, "H" - black hole found,
+// "@" - block is in the visible window
+func (b *Board) RenderMinimap(w io.Writer) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	top, left, rows, cols := b.minimapArea()
+	blockSize := maxInt(ceilDiv(rows, minimapMaxRows), ceilDiv(cols, minimapMaxCols))
+	v := b.window()
+
+	for i := top; i < top+rows; i += blockSize {
+		var line strings.Builder
+		for j := left; j < left+cols; j += blockSize {
+			block := Viewport{Top: i, Left: j, Rows: blockSize, Cols: blockSize}
+			if v.intersects(block) {
+				line.WriteString(minimapViewport)
+				continue
+			}
+			line.WriteString(b.summariseBlock(block))
+		}
+		fmt.Fprintln(w, line.String())
+	}
+}
+
+// minimapArea returns area covered by minimap. for infinite board it is explored area and visible window
+func (b *Board) minimapArea() (top, left, rows, cols int) {
+	if !b.isInfinite() {
+		return 0, 0, b.rows, b.cols
+	}
+
+	v := b.window()
+	bottom, right := v.Top+v.Rows, v.Left+v.Cols
+	top, left = v.Top, v.Left
+	for _, c := range b.cellList {
+		if c.state.isClosed() {
+			continue
+		}
+		top, left = minInt(top, c.x), minInt(left, c.y)
+		bottom, right = maxInt(bottom, c.x+1), maxInt(right, c.y+1)
+	}
+
+	return top, left, bottom - top, right - left
+}
+
+// summariseBlock returns minimap symbol of the block. cells are not generated, missing cells are unexplored
+func (b *Board) summariseBlock(block Viewport) string {
+	var total, explored int
+	for i := block.Top; i < block.Top+block.Rows; i++ {
+		for j := block.Left; j < block.Left+block.Cols; j++ {
+			if !b.isInfinite() && (i >= b.rows || j >= b.cols) {
+				continue
+			}
+			total++
+			c, ok := b.cellList[cellIdentificationKey(i, j)]
+			if !ok {
+				continue
+			}
+			if c.state.isBlackHoled() {
+				return minimapBlackHole
+			}
+			if c.state.isOpened() {
+				explored++
+			}
+		}
+	}
+
+	switch explored {
+	case 0:
+		return minimapUnexplored
+	case total:
+		return minimapExplored
+	default:
+		return minimapPartly
+	}
+}
+
+func (v Viewport) intersects(other Viewport) bool {
+	return v.Top < other.Top+other.Rows && other.Top < v.Top+v.Rows &&
+		v.Left < other.Left+other.Cols && other.Left < v.Left+v.Cols
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func clamp(v, lo, hi int) int {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+
+	return v
+}
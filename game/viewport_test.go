@@ -0,0 +1,102 @@
+package game
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoard_Pan(t *testing.T) {
+	tests := []struct {
+		name       string
+		board      func(t *testing.T) *Board
+		dRow, dCol int
+		want       Viewport
+	}{
+		{
+			name: "infinite",
+			board: func(t *testing.T) *Board {
+				b, err := NewInfiniteBoard(1, 0.1)
+				require.NoError(t, err)
+				return b
+			},
+			dRow: -20,
+			dCol: 5,
+			want: Viewport{
+				Top:  -infiniteViewRows/2 - 20,
+				Left: -infiniteViewCols/2 + 5,
+				Rows: infiniteViewRows,
+				Cols: infiniteViewCols,
+			},
+		},
+		{
+			name: "small_board_is_not_moved",
+			board: func(t *testing.T) *Board {
+				b, err := NewBoard(3, 1)
+				require.NoError(t, err)
+				return b
+			},
+			dRow: -20,
+			dCol: 5,
+			want: Viewport{Rows: 3, Cols: 3},
+		},
+		{
+			name: "big_board_is_clamped",
+			board: func(t *testing.T) *Board {
+				b, err := NewBoard(100, 1)
+				require.NoError(t, err)
+				return b
+			},
+			dRow: 200,
+			dCol: 5,
+			want: Viewport{Top: 100 - defaultViewRows, Left: 5, Rows: defaultViewRows, Cols: defaultViewCols},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.board(t)
+			b.Pan(tt.dRow, tt.dCol)
+
+			assert.Equal(t, tt.want, b.Viewport())
+		})
+	}
+}
+
+func TestBoard_CenterOnLastClick(t *testing.T) {
+	b, err := NewBoard(60, 0)
+	require.NoError(t, err)
+	require.NoError(t, b.SetViewSize(10, 10))
+
+	assert.False(t, b.CenterOnLastClick())
+
+	// click out of the visible window centers the board on it
+	err = b.Click([]int{40, 50})
+	require.NoError(t, err)
+	assert.Equal(t, Viewport{Top: 35, Left: 45, Rows: 10, Cols: 10}, b.Viewport())
+
+	b.Pan(-30, -30)
+	assert.True(t, b.CenterOnLastClick())
+	assert.Equal(t, Viewport{Top: 35, Left: 45, Rows: 10, Cols: 10}, b.Viewport())
+}
+
+func TestBoard_RenderMinimap(t *testing.T) {
+	b, err := NewBoard(48, 0)
+	require.NoError(t, err)
+	require.NoError(t, b.SetViewSize(4, 4))
+
+	var out bytes.Buffer
+	b.RenderMinimap(&out)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, minimapMaxRows)
+	assert.Equal(t, "@"+strings.Repeat(minimapUnexplored, 11), lines[0])
+
+	// board without black holes is opened entirely by one click
+	require.NoError(t, b.Click([]int{47, 47}))
+	out.Reset()
+	b.RenderMinimap(&out)
+	lines = strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, strings.Repeat(minimapExplored, 11)+"@", lines[minimapMaxRows-1])
+}
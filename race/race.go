@@ -0,0 +1,357 @@
+// Package race runs competitive games where every player clears own copy of the same board
+package race
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/proxx/game"
+	"github.com/proxx/share"
+)
+
+// MaxPlayers limits number of players in the race
+const MaxPlayers = 16
+
+var (
+	// ErrNotStarted is returned on move before all players joined
+	ErrNotStarted = errors.New("race is not started yet, waiting for players")
+	// ErrStarted is returned when player joins race which is already started
+	ErrStarted = errors.New("race is already started")
+)
+
+// Status is status of the race
+type Status string
+
+// list of race statuses
+const (
+	Waiting  Status = "waiting"
+	Running  Status = "running"
+	Finished Status = "finished"
+)
+
+// Standing is result of the player in the race
+type Standing struct {
+	Name string `json:"name"`
+	// Place is position of the player in the ranking, it is final when race is finished
+	Place int `json:"place"`
+	// Progress is share of safe cells cleared. progress of lost player is the one before the black hole
+	Progress float64 `json:"progress"`
+	Alive    bool    `json:"alive"`
+	Won      bool    `json:"won"`
+	// Time is time since start till player won or lost
+	Time time.Duration `json:"time,omitempty"`
+}
+
+// Update is sent to subscribers on every change of the race
+type Update struct {
+	Status Status `json:"status"`
+	// Players is number of players race waits for
+	Players int `json:"players"`
+	// Standings are ordered by place: winners by time, then alive players and then lost ones by progress
+	Standings []Standing `json:"standings"`
+}
+
+// Race is a game of several players on identical boards. it starts when all players joined
+// and finishes when every player won or lost
+type Race struct {
+	mu        sync.Mutex
+	board     share.Board
+	players   int
+	joined    []*Player
+	status    Status
+	startedAt time.Time
+	// now is replaced in tests
+	now func() time.Time
+
+	updates game.Notifier
+}
+
+// Player is participant of the race. its moves are made on own board
+type Player struct {
+	race  *Race
+	name  string
+	game  *game.Game
+	board *game.Board
+	// progress is kept from the moment player lost, since lost board reveals all cells
+	progress float64
+	endedAt  time.Time
+}
+
+// New creates race of given number of players on the board
+func New(b share.Board, players int) (*Race, error) {
+	if b.Infinite {
+		return nil, errors.New("infinite board can't be cleared, so it can't be raced")
+	}
+	if players < 1 || players > MaxPlayers {
+		return nil, fmt.Errorf("number of players must be from 1 to %d", MaxPlayers)
+	}
+	// board is created once to validate it, every player gets own copy when race starts
+	_, err := b.NewBoard()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Race{
+		board:   b,
+		players: players,
+		status:  Waiting,
+		now:     time.Now,
+	}, nil
+}
+
+// Board returns description of the raced board
+func (r *Race) Board() share.Board {
+	return r.board
+}
+
+// Subscribe registers fn to be called on every change of the race. updates are delivered in order,
+// outside of the race lock, so fn may call methods of the race. returned function unregisters fn
+func (r *Race) Subscribe(fn func(Update)) (unsubscribe func()) {
+	return r.updates.Subscribe(func(u interface{}) { fn(u.(Update)) })
+}
+
+// Join adds player to the race. race starts when the last player joins
+func (r *Race) Join(name string) (*Player, error) {
+	defer r.flush()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.status != Waiting {
+		return nil, ErrStarted
+	}
+	for _, p := range r.joined {
+		if p.name == name {
+			return nil, game.ErrNameTaken
+		}
+	}
+	p := &Player{race: r, name: name}
+	r.joined = append(r.joined, p)
+	if len(r.joined) == r.players {
+		err := r.start()
+		if err != nil {
+			return nil, err
+		}
+	}
+	r.changed()
+
+	return p, nil
+}
+
+// Player returns joined player by name
+func (r *Race) Player(name string) (*Player, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range r.joined {
+		if p.name == name {
+			return p, true
+		}
+	}
+
+	return nil, false
+}
+
+// start gives every player own board
+func (r *Race) start() error {
+	for _, p := range r.joined {
+		b, err := r.board.NewBoard()
+		if err != nil {
+			return err
+		}
+		p.board = b
+		p.game = game.NewGame(b)
+	}
+	r.status = Running
+	r.startedAt = r.now()
+
+	return nil
+}
+
+// Status returns status of the race
+func (r *Race) Status() Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.status
+}
+
+// Standings returns the current state of the race
+func (r *Race) Standings() Update {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.update()
+}
+
+// update returns the current state of the race, race must be locked
+func (r *Race) update() Update {
+	standings := make([]Standing, 0, len(r.joined))
+	for _, p := range r.joined {
+		s := Standing{Name: p.name, Progress: p.progress, Alive: true}
+		if p.game != nil {
+			s.Won = p.game.Won()
+			s.Alive = !p.lost()
+		}
+		if !p.endedAt.IsZero() {
+			s.Time = p.endedAt.Sub(r.startedAt)
+		}
+		standings = append(standings, s)
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		switch {
+		case a.Won != b.Won:
+			return a.Won
+		case a.Won:
+			return a.Time < b.Time
+		case a.Alive != b.Alive:
+			return a.Alive
+		case a.Progress != b.Progress:
+			return a.Progress > b.Progress
+		default:
+			// from two players lost with the same progress the one who lasted longer is higher
+			return !a.Alive && a.Time > b.Time
+		}
+	})
+	for i := range standings {
+		standings[i].Place = i + 1
+	}
+
+	return Update{Status: r.status, Players: r.players, Standings: standings}
+}
+
+// changed queues update for subscribers, race must be locked
+func (r *Race) changed() {
+	r.updates.Queue(r.update())
+}
+
+// flush delivers queued updates to subscribers
+func (r *Race) flush() {
+	r.updates.Flush()
+}
+
+// Name returns name of the player
+func (p *Player) Name() string {
+	return p.name
+}
+
+// Open opens the cell on the player's board
+func (p *Player) Open(row, col int) error {
+	return p.move(func(g *game.Game) error { return g.Open(row, col) })
+}
+
+// Flag puts flag on the cell of the player's board or removes it
+func (p *Player) Flag(row, col int) error {
+	return p.move(func(g *game.Game) error { return g.Flag(row, col) })
+}
+
+// Chord opens neighbors of the numbered cell on the player's board
+func (p *Player) Chord(row, col int) error {
+	return p.move(func(g *game.Game) error { return g.Chord(row, col) })
+}
+
+// Resign gives the race up, e.g. when player disconnects. it does nothing when player has already finished
+func (p *Player) Resign() {
+	defer p.race.flush()
+	p.race.mu.Lock()
+	defer p.race.mu.Unlock()
+
+	if p.game == nil {
+		// player leaves the race before it started
+		p.race.leave(p)
+		return
+	}
+	if p.game.IsFinished() {
+		return
+	}
+	_ = p.game.Resign()
+	p.finish()
+}
+
+// leave removes player from race waiting for players
+func (r *Race) leave(p *Player) {
+	for i, other := range r.joined {
+		if other == p {
+			r.joined = append(r.joined[:i:i], r.joined[i+1:]...)
+			r.changed()
+			return
+		}
+	}
+}
+
+// move makes move in the player's game and updates the race
+func (p *Player) move(fn func(g *game.Game) error) error {
+	r := p.race
+	defer r.flush()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case r.status == Waiting:
+		return ErrNotStarted
+	case p.game.IsFinished():
+		return game.ErrGameOver
+	}
+	err := fn(p.game)
+	if err != nil {
+		return err
+	}
+	if p.game.IsFinished() {
+		p.finish()
+		return nil
+	}
+	p.progress = p.board.Progress()
+	r.changed()
+
+	return nil
+}
+
+// finish records the end of the player's game and finishes the race when all players are done, race must be locked
+func (p *Player) finish() {
+	r := p.race
+	p.endedAt = r.now()
+	if p.game.Won() {
+		p.progress = 1
+	}
+	finished := true
+	for _, other := range r.joined {
+		if !other.game.IsFinished() {
+			finished = false
+		}
+	}
+	if finished {
+		r.status = Finished
+	}
+	r.changed()
+}
+
+func (p *Player) lost() bool {
+	return p.game.IsFinished() && !p.game.Won()
+}
+
+// Snapshot returns visible state of the player's board. ok is false before the race is started
+func (p *Player) Snapshot() (snapshot game.Snapshot, ok bool) {
+	p.race.mu.Lock()
+	defer p.race.mu.Unlock()
+
+	if p.board == nil {
+		return game.Snapshot{}, false
+	}
+
+	return p.board.Snapshot(), true
+}
+
+// Stats returns statistics and state of the player's game. ok is false before the race is started
+func (p *Player) Stats() (stats game.Stats, state game.State, ok bool) {
+	p.race.mu.Lock()
+	defer p.race.mu.Unlock()
+
+	if p.game == nil {
+		return game.Stats{}, "", false
+	}
+
+	return p.game.Stats(), p.game.GetState(), true
+}
@@ -0,0 +1,117 @@
+package race
+
+import (
+	"testing"
+	"time"
+
+	"github.com/proxx/game"
+	"github.com/proxx/share"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBoard is 3x3 board with black hole at [0 1]
+var testBoard = share.Board{Layout: &game.Layout{Rows: 3, Cols: 3, BlackHoles: [][]int{{0, 1}}}}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		board   share.Board
+		players int
+		wantErr string
+	}{
+		{name: "success", board: testBoard, players: 2},
+		{name: "infinite", board: share.Board{Infinite: true, Density: 0.1}, players: 2,
+			wantErr: "infinite board can't be cleared, so it can't be raced"},
+		{name: "no_players", board: testBoard, wantErr: "number of players must be from 1 to 16"},
+		{name: "invalid_board", board: share.Board{Size: 3, BlackHoles: 10}, players: 2,
+			wantErr: "number of blackholes [10] is bigger than max amount of board cells [9]. Quiting game"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.board, tt.players)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRace(t *testing.T) {
+	r, err := New(testBoard, 3)
+	require.NoError(t, err)
+	now := time.Unix(0, 0)
+	r.now = func() time.Time { return now }
+	var updates []Update
+	r.Subscribe(func(u Update) {
+		updates = append(updates, u)
+	})
+
+	alice, err := r.Join("alice")
+	require.NoError(t, err)
+	_, err = r.Join("alice")
+	assert.ErrorIs(t, err, game.ErrNameTaken)
+	assert.ErrorIs(t, alice.Open(2, 2), ErrNotStarted)
+	_, ok := alice.Snapshot()
+	assert.False(t, ok)
+
+	// player leaving before the start frees the place
+	leaver, err := r.Join("dave")
+	require.NoError(t, err)
+	leaver.Resign()
+	bob, err := r.Join("bob")
+	require.NoError(t, err)
+	assert.Equal(t, Waiting, r.Status())
+	carol, err := r.Join("carol")
+	require.NoError(t, err)
+	assert.Equal(t, Running, r.Status())
+	_, err = r.Join("erin")
+	assert.ErrorIs(t, err, ErrStarted)
+
+	// every player has own copy of the board
+	now = now.Add(time.Second)
+	require.NoError(t, bob.Open(2, 2))
+	snapshot, ok := alice.Snapshot()
+	require.True(t, ok)
+	assert.Equal(t, game.CellClosed, snapshot.Cells[2][2].State)
+	require.NoError(t, alice.Open(2, 2))
+	require.NoError(t, alice.Flag(0, 1))
+	require.NoError(t, alice.Open(0, 0))
+
+	now = now.Add(time.Second)
+	require.NoError(t, bob.Open(0, 1))
+	assert.ErrorIs(t, bob.Open(0, 0), game.ErrGameOver)
+	u := r.Standings()
+	assert.Equal(t, []Standing{
+		{Name: "alice", Place: 1, Progress: 0.875, Alive: true},
+		{Name: "carol", Place: 2, Alive: true},
+		{Name: "bob", Place: 3, Progress: 0.75, Time: 2 * time.Second},
+	}, u.Standings)
+
+	now = now.Add(time.Second)
+	require.NoError(t, alice.Chord(1, 1))
+	carol.Resign()
+	carol.Resign()
+	u = r.Standings()
+	assert.Equal(t, Update{
+		Status:  Finished,
+		Players: 3,
+		Standings: []Standing{
+			{Name: "alice", Place: 1, Progress: 1, Alive: true, Won: true, Time: 3 * time.Second},
+			{Name: "bob", Place: 2, Progress: 0.75, Time: 2 * time.Second},
+			{Name: "carol", Place: 3, Time: 3 * time.Second},
+		},
+	}, u)
+	stats, state, ok := alice.Stats()
+	require.True(t, ok)
+	assert.Equal(t, game.State("win"), state)
+	assert.Equal(t, 4, stats.Clicks)
+
+	// joins, leave and moves are broadcast, the last update is the final ranking
+	require.Len(t, updates, 12)
+	assert.Equal(t, Waiting, updates[0].Status)
+	assert.Equal(t, Running, updates[4].Status)
+	assert.Equal(t, u, updates[len(updates)-1])
+}
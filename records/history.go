@@ -0,0 +1,202 @@
+package records
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/proxx/game"
+)
+
+// HistoryFile is name of the games history file in records directory
+const HistoryFile = "history.jsonl"
+
+// ExportFormat is format history is exported in
+type ExportFormat string
+
+// list of export formats
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportJSON ExportFormat = "json"
+)
+
+// ParseExportFormat converts name of the format to ExportFormat
+func ParseExportFormat(name string) (ExportFormat, error) {
+	switch f := ExportFormat(name); f {
+	case ExportCSV, ExportJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q, expected %q or %q", name, ExportCSV, ExportJSON)
+	}
+}
+
+// Entry represents finished game, won or lost
+type Entry struct {
+	Player    string        `json:"player"`
+	Profile   string        `json:"profile"`
+	StartedAt time.Time     `json:"startedAt"`
+	Duration  time.Duration `json:"duration"`
+	Won       bool          `json:"won"`
+	Moves     int           `json:"moves"`
+	Clicks    int           `json:"clicks"`
+	// Opens is number of opens and chords
+	Opens       int `json:"opens"`
+	CellsOpened int `json:"cellsOpened"`
+	// Progress is share of safe cells cleared when game finished
+	Progress   float64 `json:"progress"`
+	BBBV       int     `json:"bbbv"`
+	Efficiency float64 `json:"efficiency"`
+	Score      int     `json:"score"`
+	Seed       int64   `json:"seed"`
+	HintsUsed  int     `json:"hintsUsed"`
+	UndosUsed  int     `json:"undosUsed"`
+}
+
+// History keeps all finished games in the file, one JSON document per line
+type History struct {
+	path string
+	// player and profile are used for entries recorded from game stats
+	player, profile string
+}
+
+// NewHistory creates history kept in the file at path. games are recorded for given player and profile
+func NewHistory(path, player, profile string) *History {
+	return &History{
+		path:    path,
+		player:  player,
+		profile: profile,
+	}
+}
+
+// Record appends finished game to history
+func (h *History) Record(stats game.Stats) ([]string, error) {
+	return nil, h.Add(Entry{
+		Player:      h.player,
+		Profile:     h.profile,
+		StartedAt:   stats.StartedAt,
+		Duration:    stats.Duration(),
+		Won:         stats.Won,
+		Moves:       stats.Moves,
+		Clicks:      stats.Clicks,
+		Opens:       stats.Opens,
+		CellsOpened: stats.CellsOpened,
+		Progress:    stats.Progress,
+		BBBV:        stats.BBBV,
+		Efficiency:  stats.Efficiency,
+		Score:       stats.Score,
+		Seed:        stats.Seed,
+		HintsUsed:   stats.HintsUsed,
+		UndosUsed:   stats.UndosUsed,
+	})
+}
+
+// Add appends entry to history
+func (h *History) Add(e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	return withLockedFile(h.path, func(f *os.File) error {
+		_, err := f.Seek(0, io.SeekEnd)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(append(line, '\n'))
+		if err != nil {
+			return err
+		}
+
+		return f.Sync()
+	})
+}
+
+// Entries returns games matching filter from the oldest one
+func (h *History) Entries(filter Filter) ([]Entry, error) {
+	var entries []Entry
+	err := withExistingFile(h.path, func(f *os.File) error {
+		scanner := bufio.NewScanner(f)
+		for lineNumber := 1; scanner.Scan(); lineNumber++ {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var e Entry
+			err := json.Unmarshal(line, &e)
+			if err != nil {
+				return fmt.Errorf("history file is corrupted at line %d: %w", lineNumber, err)
+			}
+			if filter.matchGame(e.Player, e.Profile, e.StartedAt, e.HintsUsed > 0 || e.UndosUsed > 0) {
+				entries = append(entries, e)
+			}
+		}
+
+		return scanner.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedAt.Before(entries[j].StartedAt)
+	})
+
+	return entries, nil
+}
+
+// csvHeader is the first line of exported CSV
+var csvHeader = []string{
+	"player", "profile", "started_at", "duration_seconds", "won", "moves", "clicks", "opens", "cells_opened",
+	"progress", "bbbv", "efficiency", "score", "seed", "hints_used", "undos_used",
+}
+
+// Export writes entries to w in given format
+func Export(w io.Writer, entries []Entry, format ExportFormat) error {
+	if format == ExportJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if entries == nil {
+			entries = []Entry{}
+		}
+
+		return encoder.Encode(entries)
+	}
+
+	cw := csv.NewWriter(w)
+	err := cw.Write(csvHeader)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		err = cw.Write([]string{
+			e.Player,
+			e.Profile,
+			e.StartedAt.Format(time.RFC3339),
+			strconv.FormatFloat(e.Duration.Seconds(), 'f', 3, 64),
+			strconv.FormatBool(e.Won),
+			strconv.Itoa(e.Moves),
+			strconv.Itoa(e.Clicks),
+			strconv.Itoa(e.Opens),
+			strconv.Itoa(e.CellsOpened),
+			strconv.FormatFloat(e.Progress, 'f', 3, 64),
+			strconv.Itoa(e.BBBV),
+			strconv.FormatFloat(e.Efficiency, 'f', 3, 64),
+			strconv.Itoa(e.Score),
+			strconv.FormatInt(e.Seed, 10),
+			strconv.Itoa(e.HintsUsed),
+			strconv.Itoa(e.UndosUsed),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
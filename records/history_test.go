@@ -0,0 +1,130 @@
+package records
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/proxx/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory_Record(t *testing.T) {
+	h := NewHistory(filepath.Join(t.TempDir(), HistoryFile), "alice", "beginner")
+	start := time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	games := []game.Stats{
+		{StartedAt: start.Add(time.Hour), EndedAt: start.Add(time.Hour + time.Minute), Won: true, Clicks: 10},
+		{StartedAt: start, EndedAt: start.Add(time.Second), Clicks: 1, Progress: 0.1},
+	}
+	for _, stats := range games {
+		notes, err := h.Record(stats)
+		require.NoError(t, err)
+		assert.Empty(t, notes)
+	}
+
+	entries, err := h.Entries(Filter{})
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Player: "alice", Profile: "beginner", StartedAt: start, Duration: time.Second, Clicks: 1, Progress: 0.1},
+		{Player: "alice", Profile: "beginner", StartedAt: start.Add(time.Hour), Duration: time.Minute, Won: true, Clicks: 10},
+	}, entries)
+
+	entries, err = h.Entries(Filter{Player: "bob"})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestSummarize(t *testing.T) {
+	entries := []Entry{
+		{Profile: "beginner", Won: true, Duration: 10 * time.Second},
+		{Profile: "beginner", Won: true, Duration: 20 * time.Second},
+		// flags are not counted as opens
+		{Profile: "expert", Clicks: 3, Opens: 1},
+		{Profile: "beginner", Won: true, Duration: 30 * time.Second},
+		{Profile: "expert", Clicks: 5, Opens: 5, Progress: 0.5},
+		{Profile: "expert", Clicks: 9, Opens: 9, Progress: 0.9},
+		{Profile: game.InfiniteProfile, Clicks: 9, Opens: 9},
+		// resigned before the first open
+		{Profile: "expert", Clicks: 1},
+		{Profile: "beginner", Won: true, Duration: 40 * time.Second},
+	}
+
+	s := Summarize(entries)
+	assert.Equal(t, Summary{
+		Played:        9,
+		Won:           4,
+		CurrentStreak: 1,
+		LongestStreak: 2,
+		Profiles: []ProfileSummary{
+			{Profile: "beginner", Played: 4, Won: 4, AverageTime: 25 * time.Second},
+			{Profile: "expert", Played: 4},
+			{Profile: game.InfiniteProfile, Played: 1},
+		},
+		Losses: []LossBucket{
+			{Name: LossNoOpens, Count: 1},
+			{Name: LossFirstClick, Count: 1},
+			{Name: LossEarly},
+			{Name: LossMiddle, Count: 1},
+			{Name: LossLate, Count: 1},
+			{Name: LossUnknown, Count: 1},
+		},
+	}, s)
+	assert.Equal(t, 4.0/9, s.WinRate())
+	assert.Equal(t, 0.0, Summarize(nil).WinRate())
+}
+
+func TestExport(t *testing.T) {
+	start := time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Player: "alice", Profile: "beginner", StartedAt: start, Duration: 1500 * time.Millisecond, Won: true,
+			Moves: 3, Clicks: 2, Opens: 2, CellsOpened: 71, Progress: 1, BBBV: 2, Efficiency: 1, Score: 900, Seed: 42},
+	}
+	tests := []struct {
+		name   string
+		format ExportFormat
+		want   string
+	}{
+		{
+			name:   "csv",
+			format: ExportCSV,
+			want: "player,profile,started_at,duration_seconds,won,moves,clicks,opens,cells_opened,progress,bbbv," +
+				"efficiency,score,seed,hints_used,undos_used\n" +
+				"alice,beginner,2021-01-01T10:00:00Z,1.500,true,3,2,2,71,1.000,2,1.000,900,42,0,0\n",
+		},
+		{
+			name:   "json",
+			format: ExportJSON,
+			want: `[
+  {
+    "player": "alice",
+    "profile": "beginner",
+    "startedAt": "2021-01-01T10:00:00Z",
+    "duration": 1500000000,
+    "won": true,
+    "moves": 3,
+    "clicks": 2,
+    "opens": 2,
+    "cellsOpened": 71,
+    "progress": 1,
+    "bbbv": 2,
+    "efficiency": 1,
+    "score": 900,
+    "seed": 42,
+    "hintsUsed": 0,
+    "undosUsed": 0
+  }
+]
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Export(&buf, entries, tt.format))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
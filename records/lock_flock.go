@@ -0,0 +1,28 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package records
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile takes exclusive advisory lock of the file. lock is released by returned function
+// or when the file is closed
+func lockFile(f *os.File) (func(), error) {
+	fd := int(f.Fd())
+	for {
+		err := syscall.Flock(fd, syscall.LOCK_EX)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return func() {
+			_ = syscall.Flock(fd, syscall.LOCK_UN)
+		}, nil
+	}
+}
@@ -0,0 +1,39 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package records
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+const (
+	lockSuffix     = ".lock"
+	lockRetryDelay = 10 * time.Millisecond
+	lockTimeout    = 10 * time.Second
+)
+
+// lockFile takes exclusive lock of the file by creating lock file next to it.
+// flock is not available on these systems, so the only atomic operation used is exclusive file creation
+func lockFile(f *os.File) (func(), error) {
+	name := f.Name() + lockSuffix
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		lock, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, filePerm)
+		if err == nil {
+			lock.Close()
+			return func() {
+				_ = os.Remove(name)
+			}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.New("lock is held too long, remove " + name + " if no game is running")
+		}
+		time.Sleep(lockRetryDelay)
+	}
+}
@@ -0,0 +1,188 @@
+package records
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/proxx/game"
+)
+
+// ScoresFile is name of the leaderboard file in records directory
+const ScoresFile = "scores.json"
+
+// SortBy is order of leaderboard
+type SortBy string
+
+// list of leaderboard orders
+const (
+	// ByTime puts the fastest games first
+	ByTime SortBy = "time"
+	// ByEfficiency puts games with the highest efficiency first
+	ByEfficiency SortBy = "efficiency"
+)
+
+// ParseSortBy converts name of the order to SortBy
+func ParseSortBy(name string) (SortBy, error) {
+	switch by := SortBy(name); by {
+	case ByTime, ByEfficiency:
+		return by, nil
+	default:
+		return "", fmt.Errorf("unknown order %q, expected %q or %q", name, ByTime, ByEfficiency)
+	}
+}
+
+// Score represents result of won game
+type Score struct {
+	Player string `json:"player"`
+	// Profile is preset name or description of custom board
+	Profile    string        `json:"profile"`
+	Date       time.Time     `json:"date"`
+	Seed       int64         `json:"seed"`
+	Duration   time.Duration `json:"duration"`
+	BBBV       int           `json:"bbbv"`
+	Efficiency float64       `json:"efficiency"`
+	Score      int           `json:"score"`
+	HintsUsed  int           `json:"hintsUsed"`
+	UndosUsed  int           `json:"undosUsed"`
+}
+
+// Assisted reports whether hints or undos were used in the game
+func (s Score) Assisted() bool {
+	return s.HintsUsed > 0 || s.UndosUsed > 0
+}
+
+// Filter selects scores and games. empty fields match everything
+type Filter struct {
+	Player  string
+	Profile string
+	// Unassisted selects only games without hints and undos
+	Unassisted bool
+	// Since selects games played after given time
+	Since time.Time
+}
+
+func (f Filter) match(s Score) bool {
+	return f.matchGame(s.Player, s.Profile, s.Date, s.Assisted())
+}
+
+func (f Filter) matchGame(player, profile string, date time.Time, assisted bool) bool {
+	return (f.Player == "" || f.Player == player) &&
+		(f.Profile == "" || f.Profile == profile) &&
+		(!f.Unassisted || !assisted) &&
+		!date.Before(f.Since)
+}
+
+// scoresDocument is content of the leaderboard file
+type scoresDocument struct {
+	Scores []Score `json:"scores"`
+}
+
+// Leaderboard keeps scores of won games in the file
+type Leaderboard struct {
+	path string
+	// player and profile are used for scores recorded from game stats
+	player, profile string
+}
+
+// NewLeaderboard creates leaderboard kept in the file at path.
+// results of games are recorded for given player and profile
+func NewLeaderboard(path, player, profile string) *Leaderboard {
+	return &Leaderboard{
+		path:    path,
+		player:  player,
+		profile: profile,
+	}
+}
+
+// Record saves score of the won game. lost games are ignored. returned notes announce new personal bests
+func (l *Leaderboard) Record(stats game.Stats) ([]string, error) {
+	if !stats.Won {
+		return nil, nil
+	}
+
+	return l.Add(Score{
+		Player:     l.player,
+		Profile:    l.profile,
+		Date:       stats.EndedAt,
+		Seed:       stats.Seed,
+		Duration:   stats.Duration(),
+		BBBV:       stats.BBBV,
+		Efficiency: stats.Efficiency,
+		Score:      stats.Score,
+		HintsUsed:  stats.HintsUsed,
+		UndosUsed:  stats.UndosUsed,
+	})
+}
+
+// Add saves score. returned notes announce new personal bests of the player in the profile
+func (l *Leaderboard) Add(score Score) ([]string, error) {
+	var doc scoresDocument
+	var notes []string
+	err := update(l.path, &doc, func() error {
+		own := Filter{Player: score.Player, Profile: score.Profile}
+		bestTime, bestEfficiency := best(doc.Scores, own, ByTime), best(doc.Scores, own, ByEfficiency)
+		if bestTime == nil || score.Duration < bestTime.Duration {
+			notes = append(notes, fmt.Sprintf("New personal best time in %s: %v", score.Profile,
+				score.Duration.Round(time.Millisecond)))
+		}
+		// efficiency is unknown on boards without metrics
+		if score.Efficiency > 0 && (bestEfficiency == nil || score.Efficiency > bestEfficiency.Efficiency) {
+			notes = append(notes, fmt.Sprintf("New personal best efficiency in %s: %.0f%%", score.Profile,
+				score.Efficiency*100))
+		}
+		doc.Scores = append(doc.Scores, score)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return notes, nil
+}
+
+// Top returns at most n best scores matching filter. all matching scores are returned if n is not positive
+func (l *Leaderboard) Top(filter Filter, by SortBy, n int) ([]Score, error) {
+	var doc scoresDocument
+	err := load(l.path, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	top := sorted(doc.Scores, filter, by)
+	if n > 0 && len(top) > n {
+		top = top[:n]
+	}
+
+	return top, nil
+}
+
+// sorted returns scores matching filter, the best first
+func sorted(scores []Score, filter Filter, by SortBy) []Score {
+	matched := make([]Score, 0, len(scores))
+	for _, s := range scores {
+		if filter.match(s) {
+			matched = append(matched, s)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if by == ByEfficiency && matched[i].Efficiency != matched[j].Efficiency {
+			return matched[i].Efficiency > matched[j].Efficiency
+		}
+
+		return matched[i].Duration < matched[j].Duration
+	})
+
+	return matched
+}
+
+// best returns the best score matching filter, nil if there are no such scores
+func best(scores []Score, filter Filter, by SortBy) *Score {
+	matched := sorted(scores, filter, by)
+	if len(matched) == 0 {
+		return nil
+	}
+
+	return &matched[0]
+}
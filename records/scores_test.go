@@ -0,0 +1,184 @@
+package records
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/proxx/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderboard_Record(t *testing.T) {
+	start := time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+	stats := func(won bool, d time.Duration, efficiency float64) game.Stats {
+		return game.Stats{StartedAt: start, EndedAt: start.Add(d), Won: won, Efficiency: efficiency, Seed: 7}
+	}
+	tests := []struct {
+		name      string
+		stats     []game.Stats
+		wantNotes []string
+		wantTop   []time.Duration
+	}{
+		{
+			name:  "first_win_is_personal_best",
+			stats: []game.Stats{stats(true, time.Minute, 0.5)},
+			wantNotes: []string{
+				"New personal best time in beginner: 1m0s",
+				"New personal best efficiency in beginner: 50%",
+			},
+			wantTop: []time.Duration{time.Minute},
+		},
+		{
+			name:      "lost_game_is_not_recorded",
+			stats:     []game.Stats{stats(false, time.Second, 1)},
+			wantNotes: nil,
+			wantTop:   []time.Duration{},
+		},
+		{
+			name:      "slower_but_more_efficient",
+			stats:     []game.Stats{stats(true, time.Minute, 0.5), stats(true, 2*time.Minute, 0.8)},
+			wantNotes: []string{"New personal best efficiency in beginner: 80%"},
+			wantTop:   []time.Duration{time.Minute, 2 * time.Minute},
+		},
+		{
+			name:      "no_personal_best",
+			stats:     []game.Stats{stats(true, time.Minute, 0.5), stats(true, 2*time.Minute, 0.4)},
+			wantNotes: nil,
+			wantTop:   []time.Duration{time.Minute, 2 * time.Minute},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLeaderboard(filepath.Join(t.TempDir(), "dir", ScoresFile), "alice", "beginner")
+
+			var notes []string
+			for _, s := range tt.stats {
+				var err error
+				notes, err = l.Record(s)
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantNotes, notes)
+
+			top, err := l.Top(Filter{}, ByTime, 0)
+			require.NoError(t, err)
+			durations := make([]time.Duration, 0, len(top))
+			for _, s := range top {
+				assert.Equal(t, "alice", s.Player)
+				assert.Equal(t, int64(7), s.Seed)
+				durations = append(durations, s.Duration)
+			}
+			assert.Equal(t, tt.wantTop, durations)
+		})
+	}
+}
+
+func TestLeaderboard_Top(t *testing.T) {
+	l := NewLeaderboard(filepath.Join(t.TempDir(), ScoresFile), "", "")
+	date := time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+	scores := []Score{
+		{Player: "alice", Profile: "beginner", Duration: 3 * time.Second, Efficiency: 0.9, Date: date},
+		{Player: "bob", Profile: "beginner", Duration: 2 * time.Second, Efficiency: 0.5, Date: date},
+		{Player: "alice", Profile: "expert", Duration: time.Second, Efficiency: 0.7, Date: date},
+		{Player: "bob", Profile: "beginner", Duration: time.Second, HintsUsed: 1, Date: date.AddDate(0, 1, 0)},
+	}
+	for _, s := range scores {
+		_, err := l.Add(s)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		by     SortBy
+		n      int
+		want   []Score
+	}{
+		{
+			name: "fastest",
+			by:   ByTime,
+			n:    2,
+			want: []Score{scores[2], scores[3]},
+		},
+		{
+			name: "most_efficient",
+			by:   ByEfficiency,
+			want: []Score{scores[0], scores[2], scores[1], scores[3]},
+		},
+		{
+			name:   "profile_unassisted",
+			filter: Filter{Profile: "beginner", Unassisted: true},
+			by:     ByTime,
+			want:   []Score{scores[1], scores[0]},
+		},
+		{
+			name:   "player_since",
+			filter: Filter{Player: "bob", Since: date.AddDate(0, 0, 1)},
+			by:     ByTime,
+			want:   []Score{scores[3]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := l.Top(tt.filter, tt.by, tt.n)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLeaderboard_ConcurrentAdd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ScoresFile)
+
+	const games = 20
+	var wg sync.WaitGroup
+	for i := 0; i < games; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// every game opens the file on its own like separate processes do
+			l := NewLeaderboard(path, "alice", "beginner")
+			_, err := l.Add(Score{Player: "alice", Duration: time.Duration(i+1) * time.Second})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	top, err := NewLeaderboard(path, "", "").Top(Filter{}, ByTime, 0)
+	require.NoError(t, err)
+	assert.Len(t, top, games)
+}
+
+func TestLeaderboard_CorruptedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ScoresFile)
+	require.NoError(t, os.WriteFile(path, []byte("{"), filePerm))
+
+	_, err := NewLeaderboard(path, "", "").Top(Filter{}, ByTime, 0)
+	assert.Error(t, err)
+}
+
+func TestReadsDontCreateFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "proxx")
+
+	scores, err := NewLeaderboard(filepath.Join(dir, ScoresFile), "alice", "beginner").Top(Filter{}, ByTime, 10)
+	require.NoError(t, err)
+	assert.Empty(t, scores)
+	entries, err := NewHistory(filepath.Join(dir, HistoryFile), "alice", "beginner").Entries(Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = os.Stat(dir)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestParseSortBy(t *testing.T) {
+	by, err := ParseSortBy("efficiency")
+	require.NoError(t, err)
+	assert.Equal(t, ByEfficiency, by)
+
+	_, err = ParseSortBy("score")
+	assert.Error(t, err)
+}
@@ -0,0 +1,118 @@
+// Package records keeps results of finished games in local files: leaderboard of the best results
+// and history of all games. Files are locked while they are read or written,
+// so games running at the same time don't corrupt them
+package records
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	appDir     = "proxx"
+	filePerm   = 0o600
+	folderPerm = 0o700
+)
+
+// DefaultDir returns directory records are kept in by default
+func DefaultDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, appDir), nil
+}
+
+// update reads JSON document from the file at path into v, calls fn and writes v back.
+// file is locked all this time. missing file is treated as empty document
+func update(path string, v interface{}, fn func() error) error {
+	return withLockedFile(path, func(f *os.File) error {
+		err := decode(f, v)
+		if err != nil {
+			return err
+		}
+		err = fn()
+		if err != nil {
+			return err
+		}
+
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		err = f.Truncate(0)
+		if err != nil {
+			return err
+		}
+		_, err = f.WriteAt(data, 0)
+		if err != nil {
+			return err
+		}
+
+		return f.Sync()
+	})
+}
+
+// load reads JSON document from the file at path into v. missing file is treated as empty document
+func load(path string, v interface{}) error {
+	return withExistingFile(path, func(f *os.File) error {
+		return decode(f, v)
+	})
+}
+
+func decode(r io.Reader, v interface{}) error {
+	err := json.NewDecoder(r).Decode(v)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("records file is corrupted: %w", err)
+	}
+
+	return nil
+}
+
+// withLockedFile opens file at path creating it with its directory if needed and calls fn holding exclusive lock
+func withLockedFile(path string, fn func(f *os.File) error) error {
+	err := os.MkdirAll(filepath.Dir(path), folderPerm)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, filePerm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return withLock(f, path, fn)
+}
+
+// withExistingFile opens file at path for reading and calls fn holding exclusive lock.
+// nothing is created on disk, fn is not called when file doesn't exist
+func withExistingFile(path string, fn func(f *os.File) error) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return withLock(f, path, fn)
+}
+
+func withLock(f *os.File, path string, fn func(f *os.File) error) error {
+	unlock, err := lockFile(f)
+	if err != nil {
+		return fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	defer unlock()
+
+	return fn(f)
+}
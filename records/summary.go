@@ -0,0 +1,120 @@
+package records
+
+import (
+	"sort"
+	"time"
+
+	"github.com/proxx/game"
+)
+
+// loss buckets by share of safe cells cleared before black hole was hit
+const (
+	// LossNoOpens is game resigned or lost by time before any cell was opened
+	LossNoOpens    = "no opens"
+	LossFirstClick = "first click"
+	LossEarly      = "early (< 25%)"
+	LossMiddle     = "middle (25-75%)"
+	LossLate       = "late (>= 75%)"
+	// LossUnknown is loss on infinite board, progress of which is unknown
+	LossUnknown = "infinite board"
+
+	earlyProgress = 0.25
+	lateProgress  = 0.75
+)
+
+// lossBuckets is order losses histogram is shown in
+var lossBuckets = []string{LossNoOpens, LossFirstClick, LossEarly, LossMiddle, LossLate, LossUnknown}
+
+// Summary represents lifetime statistics of games
+type Summary struct {
+	Played, Won int
+	// CurrentStreak is number of wins since the last loss, LongestStreak is the longest such series
+	CurrentStreak, LongestStreak int
+	Profiles                     []ProfileSummary
+	Losses                       []LossBucket
+}
+
+// ProfileSummary represents statistics of games of a single profile
+type ProfileSummary struct {
+	Profile     string
+	Played, Won int
+	// AverageTime is average duration of won games
+	AverageTime time.Duration
+}
+
+// LossBucket is number of lost games of histogram bucket
+type LossBucket struct {
+	Name  string
+	Count int
+}
+
+// WinRate returns share of won games
+func (s Summary) WinRate() float64 {
+	if s.Played == 0 {
+		return 0
+	}
+
+	return float64(s.Won) / float64(s.Played)
+}
+
+// Summarize computes lifetime statistics of games ordered from the oldest one
+func Summarize(entries []Entry) Summary {
+	var s Summary
+	profiles := make(map[string]*ProfileSummary)
+	wonTime := make(map[string]time.Duration)
+	losses := make(map[string]int)
+	for _, e := range entries {
+		p, ok := profiles[e.Profile]
+		if !ok {
+			p = &ProfileSummary{Profile: e.Profile}
+			profiles[e.Profile] = p
+		}
+		s.Played++
+		p.Played++
+		if !e.Won {
+			s.CurrentStreak = 0
+			losses[lossBucket(e)]++
+			continue
+		}
+		s.Won++
+		p.Won++
+		wonTime[e.Profile] += e.Duration
+		s.CurrentStreak++
+		if s.CurrentStreak > s.LongestStreak {
+			s.LongestStreak = s.CurrentStreak
+		}
+	}
+
+	for name, p := range profiles {
+		if p.Won > 0 {
+			p.AverageTime = wonTime[name] / time.Duration(p.Won)
+		}
+		s.Profiles = append(s.Profiles, *p)
+	}
+	sort.Slice(s.Profiles, func(i, j int) bool {
+		return s.Profiles[i].Profile < s.Profiles[j].Profile
+	})
+	for _, name := range lossBuckets {
+		s.Losses = append(s.Losses, LossBucket{Name: name, Count: losses[name]})
+	}
+
+	return s
+}
+
+func lossBucket(e Entry) string {
+	// flags can't lose the game, so only opens and chords are counted
+	switch {
+	case e.Opens == 0:
+		return LossNoOpens
+	case e.Opens == 1:
+		return LossFirstClick
+	case e.Profile == game.InfiniteProfile:
+		return LossUnknown
+	case e.Progress < earlyProgress:
+		return LossEarly
+	case e.Progress < lateProgress:
+		return LossMiddle
+	default:
+		return LossLate
+	}
+}
@@ -0,0 +1,371 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/proxx/game"
+)
+
+// format of the replay file, all integers are varints:
+//
+//	magic "PXRP", version byte
+//	seed, started at (unix milliseconds), player, profile (length and bytes)
+//	board kind byte; fixed board: rows, cols, number of black holes and their indexes row*cols+col;
+//	infinite board: density (8 bytes float64)
+//	number of actions; every action: header byte (kind, outcome, flagged, state),
+//	milliseconds since previous action, row and col for all actions but undo and forfeit,
+//	number of opened cells for open and chord
+//	CRC32 (IEEE) of everything above, 4 bytes
+const (
+	magic = "PXRP"
+	// version 2 has one more bit for action kind in the action header, version 1 is still read
+	version = 2
+
+	boardFixed    = 0
+	boardInfinite = 1
+
+	// limits protect decoder from allocating huge slices for corrupted files
+	maxBoardCells = 1 << 24
+	maxActions    = 1 << 24
+	maxStringLen  = 1 << 10
+)
+
+// ErrCorrupted is returned when replay file can't be decoded
+var ErrCorrupted = errors.New("replay is corrupted")
+
+var (
+	outcomes = []game.Outcome{game.OutcomeSafe, game.OutcomeCascade, game.OutcomeHole, game.OutcomeAlreadyOpen}
+	states   = []game.BoardState{game.BoardInProgress, game.BoardBlackHoled, game.BoardCleared, game.BoardForfeited}
+)
+
+// Save writes replay to the file at path
+func Save(path string, r *Replay) error {
+	var buf bytes.Buffer
+	err := Encode(&buf, r)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0o600)
+}
+
+// Load reads replay from the file at path
+func Load(path string) (*Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Decode(bufio.NewReader(f))
+}
+
+// Encode writes replay to w in binary format
+func Encode(w io.Writer, r *Replay) error {
+	e := &encoder{}
+	e.buf.WriteString(magic)
+	e.buf.WriteByte(version)
+	e.varint(r.Seed)
+	e.varint(r.StartedAt.UnixMilli())
+	e.writeString(r.Player)
+	e.writeString(r.Profile)
+
+	if r.Infinite() {
+		e.buf.WriteByte(boardInfinite)
+		var density [8]byte
+		binary.LittleEndian.PutUint64(density[:], math.Float64bits(r.Density))
+		e.buf.Write(density[:])
+	} else {
+		l := r.Layout
+		e.buf.WriteByte(boardFixed)
+		e.uvarint(uint64(l.Rows))
+		e.uvarint(uint64(l.Cols))
+		e.uvarint(uint64(len(l.BlackHoles)))
+		for _, h := range l.BlackHoles {
+			e.uvarint(uint64(h[0]*l.Cols + h[1]))
+		}
+	}
+
+	e.uvarint(uint64(len(r.Actions)))
+	var previous time.Duration
+	for _, a := range r.Actions {
+		header, err := actionHeader(a)
+		if err != nil {
+			return err
+		}
+		if a.At < previous {
+			return fmt.Errorf("action %v happened before the previous one", a)
+		}
+		e.buf.WriteByte(header)
+		e.uvarint(uint64((a.At - previous).Milliseconds()))
+		previous = a.At.Truncate(time.Millisecond)
+		if !a.Kind.located() {
+			continue
+		}
+		e.varint(int64(a.Row))
+		e.varint(int64(a.Col))
+		if a.Kind.opens() {
+			e.uvarint(uint64(a.Opened))
+		}
+	}
+
+	var checksum [4]byte
+	binary.LittleEndian.PutUint32(checksum[:], crc32.ChecksumIEEE(e.buf.Bytes()))
+	e.buf.Write(checksum[:])
+	_, err := w.Write(e.buf.Bytes())
+
+	return err
+}
+
+// Decode reads replay in binary format from r
+func Decode(r io.Reader) (*Replay, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < len(magic)+1+4 || string(data[:len(magic)]) != magic {
+		return nil, fmt.Errorf("%w: not a replay file", ErrCorrupted)
+	}
+	layout, ok := headerLayouts[data[len(magic)]]
+	if !ok {
+		return nil, fmt.Errorf("unsupported replay version %d", data[len(magic)])
+	}
+	body, checksum := data[:len(data)-4], binary.LittleEndian.Uint32(data[len(data)-4:])
+	if crc32.ChecksumIEEE(body) != checksum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCorrupted)
+	}
+
+	d := &decoder{r: bytes.NewReader(body[len(magic)+1:]), header: layout}
+	replay := &Replay{
+		Seed:      d.varint(),
+		StartedAt: time.UnixMilli(d.varint()),
+		Player:    d.readString(),
+		Profile:   d.readString(),
+	}
+	switch d.readByte() {
+	case boardInfinite:
+		var density [8]byte
+		d.read(density[:])
+		replay.Density = math.Float64frombits(binary.LittleEndian.Uint64(density[:]))
+	case boardFixed:
+		replay.Layout = d.layout()
+	default:
+		d.fail("unknown board kind")
+	}
+
+	count := d.count(maxActions)
+	var at time.Duration
+	for i := 0; i < count && d.err == nil; i++ {
+		a := d.action()
+		at += time.Duration(d.uvarint()) * time.Millisecond
+		a.At = at
+		if a.Kind.located() {
+			a.Row, a.Col = int(d.varint()), int(d.varint())
+		}
+		if a.Kind.opens() {
+			a.Opened = int(d.uvarint())
+		}
+		replay.Actions = append(replay.Actions, a)
+	}
+	if d.err == nil && d.r.Len() != 0 {
+		d.fail("unexpected data after actions")
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	return replay, nil
+}
+
+// headerLayout describes how action header is packed: kind takes the lowest bits,
+// then outcome (2 bits), flagged (1 bit) and state (2 bits) follow
+type headerLayout struct {
+	kindBits                               uint
+	outcomeShift, flaggedShift, stateShift uint
+}
+
+// headerLayouts are layouts of action header by replay version
+var headerLayouts = map[byte]headerLayout{
+	1: {kindBits: 2, outcomeShift: 2, flaggedShift: 4, stateShift: 5},
+	2: {kindBits: 3, outcomeShift: 3, flaggedShift: 5, stateShift: 6},
+}
+
+// actionHeader packs kind, outcome, flagged and state of the action
+func actionHeader(a Action) (byte, error) {
+	layout := headerLayouts[version]
+	if a.Kind > ActionForfeit {
+		return 0, fmt.Errorf("unknown action kind %d", a.Kind)
+	}
+	header := byte(a.Kind)
+	if a.Kind.opens() {
+		outcome := indexOf(outcomes, a.Outcome)
+		if outcome < 0 {
+			return 0, fmt.Errorf("unknown outcome %q", a.Outcome)
+		}
+		header |= byte(outcome) << layout.outcomeShift
+	}
+	if a.Flagged {
+		header |= 1 << layout.flaggedShift
+	}
+	state := -1
+	for i, s := range states {
+		if s == a.State {
+			state = i
+		}
+	}
+	if state < 0 {
+		return 0, fmt.Errorf("unknown board state %q", a.State)
+	}
+
+	return header | byte(state)<<layout.stateShift, nil
+}
+
+func indexOf(outcomes []game.Outcome, outcome game.Outcome) int {
+	for i, o := range outcomes {
+		if o == outcome {
+			return i
+		}
+	}
+
+	return -1
+}
+
+type encoder struct {
+	buf bytes.Buffer
+}
+
+func (e *encoder) uvarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	e.buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+func (e *encoder) varint(v int64) {
+	var b [binary.MaxVarintLen64]byte
+	e.buf.Write(b[:binary.PutVarint(b[:], v)])
+}
+
+func (e *encoder) writeString(s string) {
+	e.uvarint(uint64(len(s)))
+	e.buf.WriteString(s)
+}
+
+// decoder reads values until the first error. after it zero values are returned and err is kept
+type decoder struct {
+	r      *bytes.Reader
+	header headerLayout
+	err    error
+}
+
+func (d *decoder) fail(reason string) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: %s", ErrCorrupted, reason)
+	}
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		d.fail("truncated number")
+	}
+
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadVarint(d.r)
+	if err != nil {
+		d.fail("truncated number")
+	}
+
+	return v
+}
+
+func (d *decoder) readByte() byte {
+	if d.err != nil {
+		return 0
+	}
+	b, err := d.r.ReadByte()
+	if err != nil {
+		d.fail("truncated data")
+	}
+
+	return b
+}
+
+func (d *decoder) read(p []byte) {
+	if d.err != nil {
+		return
+	}
+	_, err := io.ReadFull(d.r, p)
+	if err != nil {
+		d.fail("truncated data")
+	}
+}
+
+func (d *decoder) readString() string {
+	p := make([]byte, d.count(maxStringLen))
+	d.read(p)
+
+	return string(p)
+}
+
+// count reads number of the following items checking it is not bigger than limit
+func (d *decoder) count(limit int) int {
+	n := d.uvarint()
+	if n > uint64(limit) {
+		d.fail("too many items")
+		return 0
+	}
+
+	return int(n)
+}
+
+func (d *decoder) layout() *game.Layout {
+	l := &game.Layout{Rows: d.count(maxBoardCells), Cols: d.count(maxBoardCells)}
+	if l.Rows == 0 || l.Cols == 0 || l.Rows*l.Cols > maxBoardCells {
+		d.fail("invalid board size")
+		return nil
+	}
+	holes := d.count(l.Rows * l.Cols)
+	l.BlackHoles = make([][]int, 0, holes)
+	for i := 0; i < holes && d.err == nil; i++ {
+		index := d.count(l.Rows*l.Cols - 1)
+		l.BlackHoles = append(l.BlackHoles, []int{index / l.Cols, index % l.Cols})
+	}
+
+	return l
+}
+
+func (d *decoder) action() Action {
+	header := d.readByte()
+	a := Action{
+		Kind:    ActionKind(header & (1<<d.header.kindBits - 1)),
+		Flagged: header&(1<<d.header.flaggedShift) != 0,
+	}
+	state := int(header >> d.header.stateShift)
+	if a.Kind > ActionForfeit || state >= len(states) {
+		d.fail("unknown action")
+		return a
+	}
+	a.State = states[state]
+	if a.Kind.opens() {
+		a.Outcome = outcomes[(header>>d.header.outcomeShift)&0b11]
+	}
+
+	return a
+}
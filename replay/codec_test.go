@@ -0,0 +1,143 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/proxx/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReplay() *Replay {
+	return &Replay{
+		Layout:    &game.Layout{Rows: 3, Cols: 3, BlackHoles: [][]int{{0, 1}}},
+		Seed:      -42,
+		Player:    "alice",
+		Profile:   "custom-3x3-1",
+		StartedAt: time.UnixMilli(1609495200000),
+		Actions: []Action{
+			{Kind: ActionFlag, Row: 0, Col: 1, At: 1500 * time.Millisecond, Flagged: true, State: game.BoardInProgress},
+			{Kind: ActionOpen, Row: 2, Col: 2, At: 2 * time.Second, Outcome: game.OutcomeCascade, Opened: 6,
+				State: game.BoardInProgress},
+			{Kind: ActionUndo, At: 3 * time.Second, State: game.BoardInProgress},
+			{Kind: ActionHint, Row: 0, Col: 0, At: 3 * time.Second, State: game.BoardInProgress},
+			{Kind: ActionOpen, Row: 0, Col: 0, At: 3 * time.Second, Outcome: game.OutcomeSafe, Opened: 1,
+				State: game.BoardInProgress},
+		},
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	infinite := &Replay{
+		Seed:      7,
+		Density:   0.15,
+		StartedAt: time.UnixMilli(1609495200000),
+		Actions: []Action{
+			{Kind: ActionOpen, Row: -100, Col: 3, At: time.Millisecond, Outcome: game.OutcomeSafe, Opened: 1,
+				State: game.BoardInProgress},
+			{Kind: ActionChord, Row: -100, Col: 3, At: time.Second, Outcome: game.OutcomeHole, Opened: 2,
+				State: game.BoardBlackHoled},
+		},
+	}
+	forfeited := testReplay()
+	forfeited.Actions = append(forfeited.Actions, Action{Kind: ActionForfeit, At: 4 * time.Second,
+		State: game.BoardForfeited})
+	for _, r := range []*Replay{testReplay(), infinite, forfeited} {
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, r))
+
+		got, err := Decode(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, r, got)
+	}
+}
+
+func TestDecode_Version1(t *testing.T) {
+	// 1x2 board without black holes, one open of the first cell that clears the board
+	data := []byte(magic)
+	data = append(data, 1, 0, 0, 0, 0, boardFixed, 1, 2, 0, 1, 0b1000000, 5, 0, 0, 2)
+	var checksum [4]byte
+	binary.LittleEndian.PutUint32(checksum[:], crc32.ChecksumIEEE(data))
+	data = append(data, checksum[:]...)
+
+	got, err := Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, &Replay{
+		Layout:    &game.Layout{Rows: 1, Cols: 2, BlackHoles: [][]int{}},
+		StartedAt: time.UnixMilli(0),
+		Actions: []Action{
+			{Kind: ActionOpen, At: 5 * time.Millisecond, Outcome: game.OutcomeSafe, Opened: 2, State: game.BoardCleared},
+		},
+	}, got)
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "game.pxr")
+	require.NoError(t, Save(path, testReplay()))
+
+	got, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, testReplay(), got)
+}
+
+func TestEncode_Invalid(t *testing.T) {
+	r := testReplay()
+	r.Actions[1].At = time.Second
+	assert.Error(t, Encode(&bytes.Buffer{}, r))
+
+	r = testReplay()
+	r.Actions[0].State = "unknown"
+	assert.Error(t, Encode(&bytes.Buffer{}, r))
+}
+
+func TestDecode_Corrupted(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, testReplay()))
+	valid := buf.Bytes()
+
+	tests := []struct {
+		name    string
+		data    func() []byte
+		wantErr string
+	}{
+		{
+			name:    "not_replay",
+			data:    func() []byte { return []byte("hello, world") },
+			wantErr: "replay is corrupted: not a replay file",
+		},
+		{
+			name: "unsupported_version",
+			data: func() []byte {
+				data := append([]byte(nil), valid...)
+				data[len(magic)] = 3
+				return data
+			},
+			wantErr: "unsupported replay version 3",
+		},
+		{
+			name: "changed_byte",
+			data: func() []byte {
+				data := append([]byte(nil), valid...)
+				data[len(data)-5]++
+				return data
+			},
+			wantErr: "replay is corrupted: checksum mismatch",
+		},
+		{
+			name:    "truncated",
+			data:    func() []byte { return valid[:len(valid)-1] },
+			wantErr: "replay is corrupted: checksum mismatch",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(bytes.NewReader(tt.data()))
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
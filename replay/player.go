@@ -0,0 +1,157 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/proxx/game"
+)
+
+// Player plays recorded game back on the board
+type Player struct {
+	replay *Replay
+	board  *game.Board
+	// position is number of actions applied to the board
+	position int
+}
+
+// NewPlayer creates player of the replay with board in its initial state
+func NewPlayer(r *Replay) (*Player, error) {
+	b, err := r.NewBoard()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Player{replay: r, board: b}, nil
+}
+
+// Board returns board with actions applied so far
+func (p *Player) Board() *game.Board {
+	return p.board
+}
+
+// Position returns number of actions applied to the board
+func (p *Player) Position() int {
+	return p.position
+}
+
+// Len returns number of recorded actions
+func (p *Player) Len() int {
+	return len(p.replay.Actions)
+}
+
+// Next applies the next action and returns it. io.EOF is returned when there are no more actions
+func (p *Player) Next() (Action, error) {
+	if p.position >= len(p.replay.Actions) {
+		return Action{}, io.EOF
+	}
+	a := p.replay.Actions[p.position]
+	_, err := apply(p.board, a)
+	if err != nil {
+		return Action{}, fmt.Errorf("move %d (%v): %w", p.position+1, a, err)
+	}
+	p.position++
+
+	return a, nil
+}
+
+// Seek moves to the state after given number of actions. board is rebuilt from the beginning,
+// so moving back is possible too
+func (p *Player) Seek(position int) error {
+	if position < 0 || position > len(p.replay.Actions) {
+		return fmt.Errorf("move %d is out of range 0..%d", position, len(p.replay.Actions))
+	}
+	if position < p.position {
+		b, err := p.replay.NewBoard()
+		if err != nil {
+			return err
+		}
+		p.board, p.position = b, 0
+	}
+	for p.position < position {
+		_, err := p.Next()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Play applies remaining actions keeping recorded pauses between them divided by speed.
+// onAction is called after every action. actions are applied without pauses if speed is not positive
+func (p *Player) Play(ctx context.Context, speed float64, onAction func(Action)) error {
+	var previous time.Duration
+	if p.position > 0 {
+		previous = p.replay.Actions[p.position-1].At
+	}
+	for p.position < len(p.replay.Actions) {
+		if speed > 0 {
+			pause := time.Duration(float64(p.replay.Actions[p.position].At-previous) / speed)
+			timer := time.NewTimer(pause)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		a, err := p.Next()
+		if err != nil {
+			return err
+		}
+		previous = a.At
+		onAction(a)
+	}
+
+	return nil
+}
+
+// apply makes action on the board and returns what has actually happened
+func apply(b *game.Board, a Action) (Action, error) {
+	actual := Action{Kind: a.Kind, Row: a.Row, Col: a.Col, At: a.At}
+	switch a.Kind {
+	case ActionOpen:
+		result, err := b.Open([]int{a.Row, a.Col})
+		if err != nil {
+			return Action{}, err
+		}
+		actual.Outcome, actual.Opened = result.Outcome, result.CascadeSize()
+	case ActionFlag:
+		flagged, err := b.ToggleFlag([]int{a.Row, a.Col})
+		if err != nil {
+			return Action{}, err
+		}
+		actual.Flagged = flagged
+	case ActionUndo:
+		err := b.Undo()
+		if err != nil {
+			return Action{}, err
+		}
+	case ActionChord:
+		result, err := b.Chord([]int{a.Row, a.Col})
+		if err != nil {
+			return Action{}, err
+		}
+		actual.Outcome, actual.Opened = result.Outcome, result.CascadeSize()
+	case ActionHint:
+		row, col, err := b.Hint()
+		if err != nil {
+			return Action{}, err
+		}
+		actual.Row, actual.Col = row, col
+	case ActionForfeit:
+		err := b.Forfeit()
+		if err != nil {
+			return Action{}, err
+		}
+	default:
+		return Action{}, errors.New("unknown action")
+	}
+	actual.State = b.State()
+
+	return actual, nil
+}
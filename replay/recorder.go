@@ -0,0 +1,165 @@
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/proxx/game"
+)
+
+// Recorder is playground recording every successful move made on the board.
+// all other board features are available through embedded board
+type Recorder struct {
+	*game.Board
+
+	mu     sync.Mutex
+	replay Replay
+	start  time.Time
+	// path is file replay is saved to when game is finished
+	path string
+}
+
+// NewRecorder creates recorder of the game on the board. replay is saved to the file at path
+// when game is finished, it is kept in memory only if path is empty
+func NewRecorder(b *game.Board, path, player, profile string) (*Recorder, error) {
+	r := &Recorder{
+		Board: b,
+		start: time.Now(),
+		path:  path,
+		replay: Replay{
+			Seed:    b.Seed(),
+			Player:  player,
+			Profile: profile,
+		},
+	}
+	r.replay.StartedAt = r.start
+
+	l, err := b.Layout()
+	switch {
+	case errors.Is(err, game.ErrInfiniteBoard):
+		r.replay.Density = b.Density()
+	case err != nil:
+		return nil, err
+	default:
+		r.replay.Layout = &l
+	}
+
+	return r, nil
+}
+
+// Click opens the cell and records the move
+func (r *Recorder) Click(click []int) error {
+	result, err := r.Board.Open(click)
+	if err != nil {
+		return err
+	}
+	if result.Outcome == game.OutcomeAlreadyOpen {
+		return game.ErrCellOpened
+	}
+	r.add(Action{
+		Kind:    ActionOpen,
+		Row:     click[0],
+		Col:     click[1],
+		Outcome: result.Outcome,
+		Opened:  result.CascadeSize(),
+		State:   result.State,
+	})
+
+	return nil
+}
+
+// Chord opens neighbors of the numbered cell and records the move
+func (r *Recorder) Chord(click []int) (game.ClickResult, error) {
+	result, err := r.Board.Chord(click)
+	if err != nil || result.Outcome == game.OutcomeAlreadyOpen {
+		return result, err
+	}
+	r.add(Action{
+		Kind:    ActionChord,
+		Row:     click[0],
+		Col:     click[1],
+		Outcome: result.Outcome,
+		Opened:  result.CascadeSize(),
+		State:   result.State,
+	})
+
+	return result, nil
+}
+
+// ToggleFlag toggles flag of the cell and records the move
+func (r *Recorder) ToggleFlag(click []int) (bool, error) {
+	flagged, err := r.Board.ToggleFlag(click)
+	if err != nil {
+		return false, err
+	}
+	r.add(Action{Kind: ActionFlag, Row: click[0], Col: click[1], Flagged: flagged, State: r.Board.State()})
+
+	return flagged, nil
+}
+
+// Undo reverts the last move and records it
+func (r *Recorder) Undo() error {
+	err := r.Board.Undo()
+	if err != nil {
+		return err
+	}
+	r.add(Action{Kind: ActionUndo, State: r.Board.State()})
+
+	return nil
+}
+
+// Forfeit loses the game and records it
+func (r *Recorder) Forfeit() error {
+	err := r.Board.Forfeit()
+	if err != nil {
+		return err
+	}
+	r.add(Action{Kind: ActionForfeit, State: r.Board.State()})
+
+	return nil
+}
+
+// Hint finds safe cell to open and records that hint was used
+func (r *Recorder) Hint() (row, col int, err error) {
+	row, col, err = r.Board.Hint()
+	if err != nil {
+		return 0, 0, err
+	}
+	r.add(Action{Kind: ActionHint, Row: row, Col: col, State: r.Board.State()})
+
+	return row, col, nil
+}
+
+// Replay returns copy of the game recorded so far
+func (r *Recorder) Replay() *Replay {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	replay := r.replay
+	replay.Actions = append([]Action(nil), r.replay.Actions...)
+
+	return &replay
+}
+
+// Record saves replay of the finished game to the file
+func (r *Recorder) Record(game.Stats) ([]string, error) {
+	if r.path == "" {
+		return nil, nil
+	}
+	err := Save(r.path, r.Replay())
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{fmt.Sprintf("Replay is saved to %s", r.path)}, nil
+}
+
+func (r *Recorder) add(a Action) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a.At = time.Since(r.start)
+	r.replay.Actions = append(r.replay.Actions, a)
+}
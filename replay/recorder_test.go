@@ -0,0 +1,100 @@
+package replay
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/proxx/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	b, err := game.NewBoardFromLayout(game.Layout{Rows: 3, Cols: 3, BlackHoles: [][]int{{0, 1}}})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "game.pxr")
+	r, err := NewRecorder(b, path, "alice", "custom-3x3-1")
+	require.NoError(t, err)
+
+	_, err = r.ToggleFlag([]int{0, 1})
+	require.NoError(t, err)
+	require.NoError(t, r.Click([]int{2, 2}))
+	// failed moves are not recorded
+	assert.ErrorIs(t, r.Click([]int{2, 2}), game.ErrCellOpened)
+	assert.ErrorIs(t, r.Click([]int{0, 1}), game.ErrCellFlagged)
+	require.NoError(t, r.Undo())
+	row, col, err := r.Hint()
+	require.NoError(t, err)
+	require.NoError(t, r.Click([]int{row, col}))
+	_, err = r.Chord([]int{0, 0})
+	require.NoError(t, err)
+	require.NoError(t, r.Forfeit())
+	assert.ErrorIs(t, r.Forfeit(), game.ErrGameOver)
+
+	notes, err := r.Record(game.Stats{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Replay is saved to " + path}, notes)
+
+	replay, err := Load(path)
+	require.NoError(t, err)
+	want := []Action{
+		{Kind: ActionFlag, Row: 0, Col: 1, Flagged: true, State: game.BoardInProgress},
+		{Kind: ActionOpen, Row: 2, Col: 2, Outcome: game.OutcomeCascade, Opened: 6, State: game.BoardInProgress},
+		{Kind: ActionUndo, State: game.BoardInProgress},
+		{Kind: ActionHint, Row: 0, Col: 0, State: game.BoardInProgress},
+		{Kind: ActionOpen, Row: 0, Col: 0, Outcome: game.OutcomeSafe, Opened: 1, State: game.BoardInProgress},
+		{Kind: ActionChord, Row: 0, Col: 0, Outcome: game.OutcomeCascade, Opened: 2, State: game.BoardInProgress},
+		{Kind: ActionForfeit, State: game.BoardForfeited},
+	}
+	require.Len(t, replay.Actions, len(want))
+	for i := range want {
+		want[i].At = replay.Actions[i].At
+	}
+	assert.Equal(t, want, replay.Actions)
+	assert.Equal(t, "alice", replay.Player)
+
+	// the whole game is played back on the same board
+	p, err := NewPlayer(replay)
+	require.NoError(t, err)
+	require.NoError(t, p.Play(context.Background(), 0, func(Action) {}))
+	assert.Equal(t, b.Snapshot(), p.Board().Snapshot())
+	_, err = p.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestRecorder_InfiniteBoard(t *testing.T) {
+	b, err := game.NewInfiniteBoard(3, 0.2)
+	require.NoError(t, err)
+	r, err := NewRecorder(b, "", "", game.InfiniteProfile)
+	require.NoError(t, err)
+	_ = r.Click([]int{100, -100})
+
+	notes, err := r.Record(game.Stats{})
+	require.NoError(t, err)
+	assert.Empty(t, notes)
+
+	p, err := NewPlayer(r.Replay())
+	require.NoError(t, err)
+	require.NoError(t, p.Seek(1))
+	assert.Equal(t, b.Snapshot(), p.Board().Snapshot())
+}
+
+func TestPlayer_Seek(t *testing.T) {
+	p, err := NewPlayer(testReplay())
+	require.NoError(t, err)
+
+	require.NoError(t, p.Seek(2))
+	assert.Equal(t, 2, p.Position())
+	opened := p.Board().Snapshot().Cells[2][2].State
+	assert.Equal(t, game.CellOpened, opened)
+
+	require.NoError(t, p.Seek(1))
+	assert.Equal(t, 1, p.Position())
+	assert.Equal(t, game.CellClosed, p.Board().Snapshot().Cells[2][2].State)
+	assert.Equal(t, game.CellFlagged, p.Board().Snapshot().Cells[0][1].State)
+
+	assert.EqualError(t, p.Seek(6), "move 6 is out of range 0..5")
+	assert.Equal(t, 5, p.Len())
+}
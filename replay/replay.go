@@ -0,0 +1,127 @@
+// Package replay records games move by move, saves them in compact binary files
+// and plays them back on the same board
+package replay
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/proxx/game"
+)
+
+// ActionKind is type of the recorded action
+type ActionKind byte
+
+// list of action kinds
+const (
+	ActionOpen ActionKind = iota
+	ActionFlag
+	ActionUndo
+	// ActionHint is hint shown to player, Row and Col are coordinates of the hinted cell
+	ActionHint
+	// ActionChord opens neighbors of the numbered cell
+	ActionChord
+	// ActionForfeit loses the game without click, when player gives it up or time is over
+	ActionForfeit
+)
+
+// opens reports whether action opens cells and has outcome
+func (k ActionKind) opens() bool {
+	return k == ActionOpen || k == ActionChord
+}
+
+// located reports whether action is made on the cell and has coordinates
+func (k ActionKind) located() bool {
+	return k != ActionUndo && k != ActionForfeit
+}
+
+// String returns name of the action kind
+func (k ActionKind) String() string {
+	switch k {
+	case ActionOpen:
+		return "open"
+	case ActionFlag:
+		return "flag"
+	case ActionUndo:
+		return "undo"
+	case ActionHint:
+		return "hint"
+	case ActionChord:
+		return "chord"
+	case ActionForfeit:
+		return "forfeit"
+	default:
+		return fmt.Sprintf("action(%d)", byte(k))
+	}
+}
+
+// Action represents single move of the game and its result
+type Action struct {
+	Kind ActionKind
+	// Row and Col are coordinates of opened, flagged or hinted cell, they are zero for undo and forfeit
+	Row, Col int
+	// At is time passed since game start
+	At time.Duration
+	// Outcome and Opened are result of the open and chord actions
+	Outcome game.Outcome
+	Opened  int
+	// Flagged reports whether cell is flagged after the flag action
+	Flagged bool
+	// State is board state after the action
+	State game.BoardState
+}
+
+// String describes action for player, coordinates start from 1
+func (a Action) String() string {
+	switch a.Kind {
+	case ActionOpen:
+		return fmt.Sprintf("open %d %d: %s, %d cells", a.Row+1, a.Col+1, a.Outcome, a.Opened)
+	case ActionFlag:
+		if a.Flagged {
+			return fmt.Sprintf("flag %d %d", a.Row+1, a.Col+1)
+		}
+		return fmt.Sprintf("unflag %d %d", a.Row+1, a.Col+1)
+	case ActionHint:
+		return fmt.Sprintf("hint %d %d", a.Row+1, a.Col+1)
+	case ActionChord:
+		return fmt.Sprintf("chord %d %d: %s, %d cells", a.Row+1, a.Col+1, a.Outcome, a.Opened)
+	default:
+		return a.Kind.String()
+	}
+}
+
+// Replay represents recorded game
+type Replay struct {
+	// Layout is layout of board of fixed size. it is nil for infinite board
+	Layout *game.Layout
+	// Seed is seed board was generated with, Density is density of infinite board
+	Seed    int64
+	Density float64
+
+	Player, Profile string
+	StartedAt       time.Time
+	Actions         []Action
+}
+
+// Infinite reports whether game was played on infinite board
+func (r *Replay) Infinite() bool {
+	return r.Layout == nil
+}
+
+// BoardProfile returns profile of the board the game was played on, results are grouped by it
+func (r *Replay) BoardProfile() string {
+	if r.Infinite() {
+		return game.InfiniteProfile
+	}
+
+	return game.LayoutProfile(*r.Layout)
+}
+
+// NewBoard creates board the game was played on in its initial state
+func (r *Replay) NewBoard() (*game.Board, error) {
+	if r.Infinite() {
+		return game.NewInfiniteBoard(r.Seed, r.Density)
+	}
+
+	return game.NewBoardFromLayout(*r.Layout)
+}
@@ -0,0 +1,111 @@
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/proxx/game"
+)
+
+// minActionInterval is the shortest possible time between two moves of a player
+const minActionInterval = 10 * time.Millisecond
+
+// ErrInvalid is returned when replay doesn't match the game simulated by the engine
+var ErrInvalid = errors.New("replay is not valid")
+
+// Verification represents game confirmed by the engine
+type Verification struct {
+	State game.BoardState
+	// Stats are statistics of the game computed the same way game does it
+	Stats game.Stats
+}
+
+// Verify simulates the game on the board and checks that every recorded result matches the engine.
+// replay with profile of other board, impossible timings, moves on opened cells or results the engine
+// doesn't give is rejected with ErrInvalid
+func Verify(r *Replay) (Verification, error) {
+	b, err := r.NewBoard()
+	if err != nil {
+		return Verification{}, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if profile := r.BoardProfile(); r.Profile != profile {
+		return Verification{}, fmt.Errorf("%w: profile %q is recorded for %s board", ErrInvalid, r.Profile, profile)
+	}
+
+	stats := game.Stats{StartedAt: r.StartedAt, EndedAt: r.StartedAt, Seed: r.Seed}
+	for i, a := range r.Actions {
+		if i == 0 && a.At < minActionInterval {
+			return Verification{}, fmt.Errorf("%w: move 1 (%v) is made %v after the start", ErrInvalid, a, a.At)
+		}
+		if i > 0 && a.At-r.Actions[i-1].At < minActionInterval {
+			return Verification{}, fmt.Errorf("%w: move %d (%v) is made %v after the previous one",
+				ErrInvalid, i+1, a, a.At-r.Actions[i-1].At)
+		}
+		revealed := b.Score()
+		actual, err := apply(b, a)
+		if err != nil {
+			return Verification{}, fmt.Errorf("%w: move %d (%v): %v", ErrInvalid, i+1, a, err)
+		}
+		if actual.Outcome == game.OutcomeAlreadyOpen {
+			return Verification{}, fmt.Errorf("%w: move %d (%v) is made on opened cell", ErrInvalid, i+1, a)
+		}
+		if actual != a {
+			return Verification{}, fmt.Errorf("%w: move %d is recorded as %v (%s), but engine gives %v (%s)",
+				ErrInvalid, i+1, a, a.State, actual, actual.State)
+		}
+
+		count(&stats, a)
+		if a.Kind == ActionUndo {
+			// cells opened by undone move are not counted, as game does it
+			stats.CellsOpened -= revealed - b.Score()
+		}
+		stats.EndedAt = r.StartedAt.Add(a.At)
+	}
+	// score depends on the duration, so game without start time or finished in the future can't be scored
+	if total := stats.Duration(); total < time.Duration(len(r.Actions))*minActionInterval {
+		return Verification{}, fmt.Errorf("%w: %d moves are made in %v", ErrInvalid, len(r.Actions), total)
+	}
+	if stats.EndedAt.After(time.Now()) {
+		return Verification{}, fmt.Errorf("%w: game ends in the future", ErrInvalid)
+	}
+
+	stats.Won = b.State() == game.BoardCleared
+	stats.Cleared = b.Score()
+	stats.Progress = b.Progress()
+	stats.Difficulty = game.Difficulty(b.Density())
+	stats.Score = stats.ComputeScore()
+	if m, err := b.Metrics(); err == nil && b.State() != game.BoardInProgress {
+		stats.BBBV = m.BBBV
+		if stats.Clicks > 0 {
+			stats.Efficiency = float64(m.BBBV) / float64(stats.Clicks)
+		}
+	}
+
+	return Verification{State: b.State(), Stats: stats}, nil
+}
+
+// count adds verified action to game statistics
+func count(stats *game.Stats, a Action) {
+	switch a.Kind {
+	case ActionOpen, ActionChord:
+		stats.Moves++
+		stats.Clicks++
+		stats.Opens++
+		// game counts opened safe cells only, opened black hole is not one of them
+		if a.Outcome != game.OutcomeHole {
+			stats.CellsOpened += a.Opened
+		}
+	case ActionFlag:
+		stats.Moves++
+		stats.Clicks++
+		if a.Flagged {
+			stats.FlagsPlaced++
+		}
+	case ActionUndo:
+		stats.Moves++
+		stats.UndosUsed++
+	case ActionHint:
+		stats.HintsUsed++
+	}
+}
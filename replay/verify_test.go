@@ -0,0 +1,316 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/proxx/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+
+// wonReplay is game on the board
+//
+//	1 H 1
+//	1 1 1
+//	0 0 0
+func wonReplay() *Replay {
+	return &Replay{
+		Profile:   "custom-3x3-1",
+		StartedAt: start,
+		Layout:    &game.Layout{Rows: 3, Cols: 3, BlackHoles: [][]int{{0, 1}}},
+		Actions: []Action{
+			{Kind: ActionFlag, Row: 0, Col: 1, At: time.Second, Flagged: true, State: game.BoardInProgress},
+			{Kind: ActionOpen, Row: 2, Col: 2, At: 2 * time.Second, Outcome: game.OutcomeCascade, Opened: 6,
+				State: game.BoardInProgress},
+			{Kind: ActionHint, Row: 0, Col: 0, At: 3 * time.Second, State: game.BoardInProgress},
+			{Kind: ActionOpen, Row: 0, Col: 0, At: 4 * time.Second, Outcome: game.OutcomeSafe, Opened: 1,
+				State: game.BoardInProgress},
+			{Kind: ActionOpen, Row: 0, Col: 2, At: 5 * time.Second, Outcome: game.OutcomeSafe, Opened: 1,
+				State: game.BoardCleared},
+		},
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		tamper  func(r *Replay)
+		want    Verification
+		wantErr string
+	}{
+		{
+			name: "won",
+			want: Verification{
+				State: game.BoardCleared,
+				Stats: game.Stats{
+					StartedAt:   start,
+					EndedAt:     start.Add(5 * time.Second),
+					Moves:       4,
+					Clicks:      4,
+					Opens:       3,
+					CellsOpened: 8,
+					Cleared:     8,
+					FlagsPlaced: 1,
+					HintsUsed:   1,
+					Won:         true,
+					Difficulty:  1 + 10.0/9,
+					// (8 cells * 10 + 500 win bonus + 3 seconds left * 10) * difficulty - 50 for hint
+					Score:      1238,
+					Progress:   1,
+					BBBV:       3,
+					Efficiency: 0.75,
+				},
+			},
+		},
+		{
+			name: "lost",
+			tamper: func(r *Replay) {
+				r.Actions = append(r.Actions[:1],
+					Action{Kind: ActionUndo, At: 2 * time.Second, State: game.BoardInProgress},
+					Action{Kind: ActionOpen, Row: 0, Col: 1, At: 3 * time.Second, Outcome: game.OutcomeHole, Opened: 1,
+						State: game.BoardBlackHoled})
+			},
+			want: Verification{
+				State: game.BoardBlackHoled,
+				Stats: game.Stats{
+					StartedAt:   start,
+					EndedAt:     start.Add(3 * time.Second),
+					Moves:       3,
+					Clicks:      2,
+					Opens:       1,
+					FlagsPlaced: 1,
+					UndosUsed:   1,
+					Difficulty:  1 + 10.0/9,
+					BBBV:        3,
+					Efficiency:  1.5,
+				},
+			},
+		},
+		{
+			name: "impossible_timing",
+			tamper: func(r *Replay) {
+				r.Actions[3].At = r.Actions[2].At + time.Millisecond
+			},
+			wantErr: "replay is not valid: move 4 (open 1 1: safe, 1 cells) is made 1ms after the previous one",
+		},
+		{
+			name: "first_move_timing",
+			tamper: func(r *Replay) {
+				r.Actions[0].At = time.Millisecond
+			},
+			wantErr: "replay is not valid: move 1 (flag 1 2) is made 1ms after the start",
+		},
+		{
+			name: "no_start_time",
+			tamper: func(r *Replay) {
+				r.StartedAt = time.Time{}
+			},
+			wantErr: "replay is not valid: 5 moves are made in 0s",
+		},
+		{
+			name: "ends_in_future",
+			tamper: func(r *Replay) {
+				r.StartedAt = time.Now()
+			},
+			wantErr: "replay is not valid: game ends in the future",
+		},
+		{
+			name: "forged_profile",
+			tamper: func(r *Replay) {
+				r.Profile = "expert"
+			},
+			wantErr: `replay is not valid: profile "expert" is recorded for custom-3x3-1 board`,
+		},
+		{
+			name: "opened_cell",
+			tamper: func(r *Replay) {
+				r.Actions[3].Row, r.Actions[3].Col = 2, 1
+			},
+			wantErr: "replay is not valid: move 4 (open 3 2: safe, 1 cells) is made on opened cell",
+		},
+		{
+			name: "claimed_win",
+			tamper: func(r *Replay) {
+				r.Actions = r.Actions[:4]
+				r.Actions[3].State = game.BoardCleared
+			},
+			wantErr: "replay is not valid: move 4 is recorded as open 1 1: safe, 1 cells (cleared), " +
+				"but engine gives open 1 1: safe, 1 cells (inProgress)",
+		},
+		{
+			name: "move_after_game_over",
+			tamper: func(r *Replay) {
+				r.Actions = append(r.Actions, Action{Kind: ActionFlag, At: time.Minute, Flagged: true,
+					State: game.BoardCleared})
+			},
+			wantErr: "replay is not valid: move 6 (flag 1 1): game is over",
+		},
+		{
+			name: "invalid_board",
+			tamper: func(r *Replay) {
+				r.Layout.BlackHoles = [][]int{{5, 5}}
+			},
+			wantErr: "replay is not valid: black hole [5 5] is out of the board [3 x 3]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := wonReplay()
+			if tt.tamper != nil {
+				tt.tamper(r)
+			}
+
+			got, err := Verify(r)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.True(t, errors.Is(err, ErrInvalid))
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want.Stats.Difficulty, got.Stats.Difficulty, 1e-9)
+			got.Stats.Difficulty = tt.want.Stats.Difficulty
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVerify_SameStatsAsGame(t *testing.T) {
+	tests := []struct {
+		name  string
+		moves func(g *game.Game) error
+	}{
+		{
+			name: "lost",
+			moves: func(g *game.Game) error {
+				if err := g.Open(2, 2); err != nil {
+					return err
+				}
+				return g.Open(0, 1)
+			},
+		},
+		{
+			name: "lost_by_chord",
+			moves: func(g *game.Game) error {
+				if err := g.Open(2, 2); err != nil {
+					return err
+				}
+				if err := g.Flag(0, 0); err != nil {
+					return err
+				}
+				return g.Chord(1, 0)
+			},
+		},
+		{
+			name: "won_after_undo",
+			moves: func(g *game.Game) error {
+				for _, cell := range [][]int{{2, 2}, {0, 0}} {
+					if err := g.Open(cell[0], cell[1]); err != nil {
+						return err
+					}
+				}
+				if err := g.Undo(); err != nil {
+					return err
+				}
+				for _, cell := range [][]int{{0, 0}, {0, 2}} {
+					if err := g.Open(cell[0], cell[1]); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name: "resigned",
+			moves: func(g *game.Game) error {
+				if err := g.Open(2, 2); err != nil {
+					return err
+				}
+				return g.Resign()
+			},
+		},
+		{
+			name: "won",
+			moves: func(g *game.Game) error {
+				for _, cell := range [][]int{{2, 2}, {0, 0}, {0, 2}} {
+					if err := g.Open(cell[0], cell[1]); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := game.NewBoardFromLayout(*wonReplay().Layout)
+			require.NoError(t, err)
+			r, err := NewRecorder(b, "", "", "custom-3x3-1")
+			require.NoError(t, err)
+			g := game.NewGame(r)
+			require.NoError(t, tt.moves(g))
+			require.True(t, g.IsFinished())
+
+			// moves of the test are faster than human ones
+			replay := r.Replay()
+			replay.StartedAt = start
+			for i := range replay.Actions {
+				replay.Actions[i].At = time.Duration(i+1) * time.Second
+			}
+			got, err := Verify(replay)
+			require.NoError(t, err)
+			assert.Equal(t, b.State(), got.State)
+			want := g.Stats()
+			assert.Equal(t, want.Won, got.Stats.Won)
+			assert.Equal(t, want.Moves, got.Stats.Moves)
+			assert.Equal(t, want.Clicks, got.Stats.Clicks)
+			assert.Equal(t, want.Opens, got.Stats.Opens)
+			assert.Equal(t, want.CellsOpened, got.Stats.CellsOpened)
+			assert.Equal(t, want.Cleared, got.Stats.Cleared)
+			assert.Equal(t, want.FlagsPlaced, got.Stats.FlagsPlaced)
+			assert.Equal(t, want.BBBV, got.Stats.BBBV)
+			assert.Equal(t, want.Efficiency, got.Stats.Efficiency)
+		})
+	}
+}
+
+func TestVerify_Timeout(t *testing.T) {
+	b, err := game.NewBoardFromLayout(*wonReplay().Layout)
+	require.NoError(t, err)
+	r, err := NewRecorder(b, "", "", "custom-3x3-1")
+	require.NoError(t, err)
+	g := game.NewGame(r, game.WithMoveTimeout(50*time.Millisecond, game.TimeoutForfeit))
+
+	// player opens a cell and then doesn't make a move in time
+	in, out := io.Pipe()
+	defer out.Close()
+	go func() {
+		_, _ = out.Write([]byte("3 3\n"))
+	}()
+	require.NoError(t, g.Run(context.Background(), in))
+	require.Equal(t, game.BoardForfeited, b.State())
+
+	replay := r.Replay()
+	require.Len(t, replay.Actions, 2)
+	// moves of the test are faster than human ones
+	replay.StartedAt = start
+	replay.Actions[0].At = time.Second
+	replay.Actions[1].At += time.Second
+	assert.Equal(t, ActionForfeit, replay.Actions[1].Kind)
+	assert.Equal(t, game.BoardForfeited, replay.Actions[1].State)
+
+	got, err := Verify(replay)
+	require.NoError(t, err)
+	assert.Equal(t, game.BoardForfeited, got.State)
+	want := g.Stats()
+	assert.False(t, got.Stats.Won)
+	assert.Equal(t, want.Moves, got.Stats.Moves)
+	assert.Equal(t, want.CellsOpened, got.Stats.CellsOpened)
+	assert.Equal(t, want.Cleared, got.Stats.Cleared)
+	assert.Equal(t, want.BBBV, got.Stats.BBBV)
+}
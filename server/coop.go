@@ -0,0 +1,208 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/proxx/coop"
+)
+
+// MessageTeam has status of the team game, it is sent to players after every move, join and leave
+const MessageTeam MessageType = "team"
+
+// CoopSettings describes new team game: the board and mode, free or turns
+type CoopSettings struct {
+	Settings
+	Mode string `json:"mode,omitempty"`
+}
+
+// CoopState is team game as players see it
+type CoopState struct {
+	ID      string      `json:"id"`
+	Profile string      `json:"profile"`
+	Team    coop.Status `json:"team"`
+	Game    GameState   `json:"game"`
+}
+
+// coopRoom is a team game held by the server
+type coopRoom struct {
+	id      string
+	profile string
+	team    *coop.Team
+}
+
+func (room *coopRoom) state() CoopState {
+	return CoopState{ID: room.id, Profile: room.profile, Team: room.team.Status(), Game: room.gameState()}
+}
+
+// gameState returns state of the shared board
+func (room *coopRoom) gameState() GameState {
+	status := room.team.Status()
+
+	return newGameState(room.id, room.profile, status.State, room.team.Snapshot(), room.team.Stats())
+}
+
+func newCoopRoom(settings CoopSettings) (*coopRoom, error) {
+	mode, err := coop.ParseMode(settings.Mode)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := settings.board()
+	if err != nil {
+		return nil, err
+	}
+	b, err := newBoard(shared)
+	if err != nil {
+		return nil, err
+	}
+	team, err := coop.New(b, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &coopRoom{profile: shared.Profile(), team: team}, nil
+}
+
+// handleCoops creates new team game: POST /api/coop
+func (s *Server) handleCoops(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+	var settings CoopSettings
+	err := decodeBody(w, r, &settings)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	delay, err := settings.spectateDelay()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	room, err := newCoopRoom(settings)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	room.id, err = newSessionID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	err = s.add(room.id, room, delay)
+	if errors.Is(err, errTooManySessions) {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, room.state())
+}
+
+// handleCoop serves existing team game:
+//
+//	GET /api/coop/{id} returns status of the team and the board
+//	GET /api/coop/{id}/live?player={name} joins the team over WebSocket, see handleCoopLive
+func (s *Server) handleCoop(w http.ResponseWriter, r *http.Request) {
+	parts, ok := pathParts(r, coopPrefix)
+	if !ok || len(parts) == 2 && parts[1] != "live" {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+	v, _ := s.get(parts[0])
+	room, ok := v.(*coopRoom)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("team game is not found or expired"))
+		return
+	}
+	if len(parts) == 1 {
+		writeJSON(w, http.StatusOK, room.state())
+		return
+	}
+	s.handleCoopLive(w, r, room)
+}
+
+// handleCoopLive joins player to the team. every player receives board events with name of the player
+// who caused them, and after every move status of the team and the board. commands are open, flag and chord,
+// player who disconnects leaves the team
+func (s *Server) handleCoopLive(w http.ResponseWriter, r *http.Request, room *coopRoom) {
+	name := r.URL.Query().Get("player")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, errors.New("player name is required"))
+		return
+	}
+
+	watch := newWatcher()
+	unsubscribe := room.team.Subscribe(room.sender(watch))
+	defer unsubscribe()
+	p, err := room.team.Join(name)
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	defer p.Leave()
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// upgrader has already responded with error
+		return
+	}
+
+	s.serveLive(conn, room.id, watch, func(c Command) error {
+		switch c.Action {
+		case "open":
+			return p.Open(c.Row, c.Col)
+		case "flag":
+			return p.Flag(c.Row, c.Col)
+		case "chord":
+			return p.Chord(c.Row, c.Col)
+		default:
+			return fmt.Errorf("unknown team action %q", c.Action)
+		}
+	})
+}
+
+// sender returns subscriber which sends board events with name of the player who caused them,
+// and status of the team followed by the board
+func (room *coopRoom) sender(watch *watcher) func(coop.Update) {
+	return func(u coop.Update) {
+		if u.Event != nil {
+			m := eventMessage(u.Event)
+			m.Player = u.Player
+			watch.send(m)
+			return
+		}
+		state := room.gameState()
+		watch.send(Message{Type: MessageTeam, Team: &u.Status})
+		watch.send(Message{Type: MessageState, State: &state})
+	}
+}
+
+// spectate sends status of the team and the board, and then every update of the game
+func (room *coopRoom) spectate(watch *watcher) (stop func()) {
+	send := room.sender(watch)
+	unsubscribe := room.team.Subscribe(send)
+	send(coop.Update{Status: room.team.Status()})
+
+	return unsubscribe
+}
+
+func (room *coopRoom) info() SessionInfo {
+	status := room.team.Status()
+	info := SessionInfo{Kind: kindCoop, Profile: room.profile, State: string(status.State)}
+	for _, p := range status.Players {
+		if !p.Left {
+			info.Players = append(info.Players, p.Name)
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/proxx/coop"
+	"github.com/proxx/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// joinTeam connects player to the team game and reads messages sent on join
+func joinTeam(t *testing.T, srv *httptest.Server, id, player string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/coop/" + id + "/live?player=" + player
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	t.Cleanup(func() { conn.Close() })
+	_, _ = readUntilState(t, conn)
+
+	return conn
+}
+
+// readMove reads messages of one move: attributed events, team status and the board
+func readMove(t *testing.T, conn *websocket.Conn) ([]string, coop.Status, *GameState) {
+	var events []string
+	for {
+		m := readMessage(t, conn)
+		if m.Type == MessageTeam {
+			_, state := readUntilState(t, conn)
+			return events, *m.Team, state
+		}
+		events = append(events, m.Player+" "+string(m.Type))
+	}
+}
+
+func TestServer_Coop(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	var created CoopState
+	body := `{"code":"` + testCode(t) + `","mode":"turns"}`
+	require.Equal(t, http.StatusCreated, request(t, srv, http.MethodPost, "/api/coop", body, &created))
+	assert.Equal(t, coop.Status{State: "inProgress", Mode: coop.Turns}, created.Team)
+	assert.Equal(t, []string{"###", "###", "###"}, created.Game.Cells)
+
+	alice := joinTeam(t, srv, created.ID, "alice")
+	bob := joinTeam(t, srv, created.ID, "bob")
+	// alice is told that bob joined
+	_, status, _ := readMove(t, alice)
+	assert.Len(t, status.Players, 2)
+
+	require.NoError(t, bob.WriteJSON(Command{Action: "open", Row: 2, Col: 2}))
+	m := readMessage(t, bob)
+	assert.Equal(t, Message{Type: MessageError, Error: game.ErrNotYourTurn.Error()}, m)
+
+	require.NoError(t, alice.WriteJSON(Command{Action: "open", Row: 2, Col: 2}))
+	for _, conn := range []*websocket.Conn{alice, bob} {
+		events, status, state := readMove(t, conn)
+		assert.Len(t, events, 7)
+		assert.Equal(t, "alice cascadeFinished", events[6])
+		assert.Equal(t, "bob", status.Turn)
+		assert.Equal(t, []string{"###", "111", "000"}, state.Cells)
+	}
+
+	// black hole found by bob loses the game for both
+	require.NoError(t, bob.WriteJSON(Command{Action: "open", Row: 0, Col: 1}))
+	for _, conn := range []*websocket.Conn{alice, bob} {
+		events, status, state := readMove(t, conn)
+		assert.Equal(t, []string{"bob gameLost"}, events)
+		assert.Equal(t, "bob", status.LostBy)
+		assert.Equal(t, []coop.PlayerStats{{Name: "alice", Moves: 1, CellsOpened: 6}, {Name: "bob", Moves: 1}},
+			status.Players)
+		assert.Equal(t, []string{"1*1", "111", "000"}, state.Cells)
+	}
+
+	// player who disconnects leaves the team
+	require.NoError(t, bob.Close())
+	_, status, _ = readMove(t, alice)
+	assert.True(t, status.Players[1].Left)
+
+	var got CoopState
+	require.Equal(t, http.StatusOK, request(t, srv, http.MethodGet, "/api/coop/"+created.ID, "", &got))
+	assert.Equal(t, status, got.Team)
+
+	var errResp errorResponse
+	body = `{"preset":"beginner","mode":"chaos"}`
+	require.Equal(t, http.StatusBadRequest, request(t, srv, http.MethodPost, "/api/coop", body, &errResp))
+	assert.Equal(t, `unknown mode "chaos", expected free or turns`, errResp.Error)
+}
@@ -0,0 +1,23 @@
+package server
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// web is browser frontend playing the game over the API, it is served from the root
+//
+//go:embed web
+var web embed.FS
+
+// frontend serves files of the browser frontend
+func frontend() http.Handler {
+	files, err := fs.Sub(web, "web")
+	if err != nil {
+		// directory is embedded at build time, so it always exists
+		panic(err)
+	}
+
+	return http.FileServer(http.FS(files))
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Frontend(t *testing.T) {
+	tests := []struct {
+		path        string
+		wantStatus  int
+		contentType string
+		contains    string
+	}{
+		{path: "/", wantStatus: http.StatusOK, contentType: "text/html", contains: "<title>Proxx</title>"},
+		{path: "/app.js", wantStatus: http.StatusOK, contentType: "javascript",
+			contains: `const api = "api/games"`},
+		{path: "/style.css", wantStatus: http.StatusOK, contentType: "text/css", contains: "#board"},
+		{path: "/missing.js", wantStatus: http.StatusNotFound},
+	}
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := srv.Client().Get(srv.URL + tt.path)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.contains != "" {
+				assert.Contains(t, resp.Header.Get("Content-Type"), tt.contentType)
+				assert.Contains(t, string(body), tt.contains)
+			}
+		})
+	}
+}
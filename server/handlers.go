@@ -0,0 +1,185 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/proxx/game"
+)
+
+// maxBodySize limits size of request body
+const maxBodySize = 1 << 16
+
+// Move is body of open, flag and chord requests. coordinates start from zero
+type Move struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
+// errorResponse is body of failed request
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// handleGames creates new game: POST /api/games
+func (s *Server) handleGames(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+	var settings Settings
+	err := decodeBody(w, r, &settings)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	delay, err := settings.spectateDelay()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	sess, err := newSession(settings)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	sess.id, err = newSessionID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	err = s.add(sess.id, sess, delay)
+	if errors.Is(err, errTooManySessions) {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	writeJSON(w, http.StatusCreated, sess.state())
+}
+
+// handleGame serves existing game:
+//
+//	GET /api/games/{id} returns state of the game
+//	DELETE /api/games/{id} ends the session
+//	POST /api/games/{id}/{open,flag,chord} with Move body makes move
+//	POST /api/games/{id}/{undo,resign} reverts the last move or gives the game up
+//	GET /api/games/{id}/live opens WebSocket connection to the game, see handleLive
+func (s *Server) handleGame(w http.ResponseWriter, r *http.Request) {
+	parts, ok := pathParts(r, gamesPrefix)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	id := parts[0]
+	if len(parts) == 1 && r.Method == http.MethodDelete {
+		// ids of other sessions are not games and can't be deleted here
+		if _, ok := s.session(id); !ok || !s.remove(id) {
+			writeError(w, http.StatusNotFound, errors.New("game is not found"))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	sess, ok := s.session(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("game is not found or expired"))
+		return
+	}
+	if len(parts) == 1 {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+			return
+		}
+		sess.mu.Lock()
+		defer sess.mu.Unlock()
+		writeJSON(w, http.StatusOK, sess.state())
+		return
+	}
+	if parts[1] == "live" && r.Method == http.MethodGet {
+		s.handleLive(w, r, id, sess)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+	s.handleAction(w, r, sess, parts[1])
+}
+
+// handleAction makes move in the game and responds with new state
+func (s *Server) handleAction(w http.ResponseWriter, r *http.Request, sess *session, action string) {
+	if _, ok := actions[action]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown action %q", action))
+		return
+	}
+	var m Move
+	if movesCell(action) {
+		err := decodeBody(w, r, &m)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	err := sess.do(action, m)
+	if err != nil {
+		writeError(w, moveErrorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sess.state())
+}
+
+// pathParts splits path after prefix to id and optional action
+func pathParts(r *http.Request, prefix string) ([]string, bool) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, prefix+"/"), "/")
+
+	return parts, len(parts) <= 2 && parts[0] != ""
+}
+
+// moveErrorStatus returns response status of failed move
+func moveErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, game.ErrInvalidClick), errors.Is(err, game.ErrOutOfBounds):
+		return http.StatusBadRequest
+	default:
+		// the move is valid request but it is not allowed by rules in current state of the game,
+		// e.g. cell is already opened or game is over
+		return http.StatusConflict
+	}
+}
+
+// decodeBody decodes JSON body of the request
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(v)
+	if err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// response is already started, so error can't be reported to the client
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
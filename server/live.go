@@ -0,0 +1,231 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/proxx/coop"
+	"github.com/proxx/game"
+	"github.com/proxx/race"
+	"github.com/proxx/versus"
+)
+
+// timings of live connection
+const (
+	writeTimeout = 10 * time.Second
+	// pingPeriod is how often client is pinged. connected client keeps session from expiring
+	pingPeriod = 30 * time.Second
+	// pongTimeout is time client has to answer ping
+	pongTimeout = pingPeriod + writeTimeout
+	// watcherBuffer is number of messages queued for client. client that can't keep up is disconnected
+	watcherBuffer = 256
+	// maxCommandSize limits size of message from client
+	maxCommandSize = 1 << 12
+)
+
+// MessageType is type of message sent over live connection
+type MessageType string
+
+// list of message types besides board events, which are sent with type of the event, e.g. cellOpened
+const (
+	// MessageState has state of the game. it is sent on connect and after every action made in the game
+	MessageState MessageType = "state"
+	// MessageError has error of action sent over the connection
+	MessageError MessageType = "error"
+)
+
+// Message is sent over live connection. board events are sent as they happen and then state of the game follows
+type Message struct {
+	Type MessageType `json:"type"`
+	// Event has fields of the board event, e.g. row, col and value of the opened cell
+	Event map[string]interface{} `json:"event,omitempty"`
+	// Player caused the event in game of several players
+	Player string       `json:"player,omitempty"`
+	State  *GameState   `json:"state,omitempty"`
+	Race   *race.Update `json:"race,omitempty"`
+	Team   *coop.Status `json:"team,omitempty"`
+	Versus *VersusState `json:"versus,omitempty"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// Command is action sent over live connection. Row and Col are needed only by open, flag and chord
+type Command struct {
+	Action string `json:"action"`
+	Row    int    `json:"row"`
+	Col    int    `json:"col"`
+}
+
+// eventMessage converts board event to message
+func eventMessage(e game.Event) Message {
+	m := Message{Type: MessageType(e.Kind())}
+	switch e := e.(type) {
+	case game.CellOpenedEvent:
+		m.Event = map[string]interface{}{"row": e.Row, "col": e.Col, "value": e.Value}
+	case game.CascadeFinishedEvent:
+		m.Event = map[string]interface{}{"row": e.Row, "col": e.Col, "opened": e.Opened}
+	case game.FlagToggledEvent:
+		m.Event = map[string]interface{}{"row": e.Row, "col": e.Col, "flagged": e.Flagged}
+	case game.GameWonEvent:
+		m.Event = map[string]interface{}{"revealed": e.Revealed}
+	case game.GameLostEvent:
+		m.Event = map[string]interface{}{"row": e.Row, "col": e.Col, "forfeited": e.Forfeited}
+	case game.MoveUndoneEvent:
+		m.Event = map[string]interface{}{"move": e.Move, "row": e.Row, "col": e.Col, "restored": e.Restored,
+			"closed": e.Closed}
+	case versus.HoleFoundEvent:
+		m.Event = map[string]interface{}{"row": e.Row, "col": e.Col, "eliminated": e.Eliminated}
+	default:
+	}
+
+	return m
+}
+
+// watcher queues messages for live connection, so the game never waits for the network
+type watcher struct {
+	out  chan Message
+	done chan struct{}
+	once sync.Once
+	// spectator gets messages without id of the session, since the id lets players make moves
+	spectator bool
+}
+
+func newWatcher() *watcher {
+	return &watcher{
+		out:  make(chan Message, watcherBuffer),
+		done: make(chan struct{}),
+	}
+}
+
+// send queues message. watcher with full queue is closed
+func (w *watcher) send(m Message) {
+	if w.spectator {
+		m = withoutID(m)
+	}
+	select {
+	case <-w.done:
+	case w.out <- m:
+	default:
+		w.close()
+	}
+}
+
+func (w *watcher) close() {
+	w.once.Do(func() {
+		close(w.done)
+	})
+}
+
+// handleLive serves live connection to the game: GET /api/games/{id}/live upgraded to WebSocket.
+// client receives board events and game state and sends commands
+func (s *Server) handleLive(w http.ResponseWriter, r *http.Request, id string, sess *session) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// upgrader has already responded with error
+		return
+	}
+	watch := newWatcher()
+	stop := sess.spectate(watch)
+	defer stop()
+
+	s.serveLive(conn, id, watch, func(c Command) error {
+		sess.mu.Lock()
+		defer sess.mu.Unlock()
+
+		return sess.do(c.Action, Move{Row: c.Row, Col: c.Col})
+	})
+}
+
+// serveLive delivers messages queued by watcher and passes commands of client to handle
+// until connection is closed or value with id is removed from the server
+func (s *Server) serveLive(conn *websocket.Conn, id string, watch *watcher, handle func(c Command) error) {
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		s.writeLive(conn, id, watch)
+	}()
+	s.readLive(conn, id, watch, handle)
+	watch.close()
+	<-written
+}
+
+// readLive passes commands sent by client to handle until connection is closed. errors are sent back to client
+func (s *Server) readLive(conn *websocket.Conn, id string, watch *watcher, handle func(c Command) error) {
+	conn.SetReadLimit(maxCommandSize)
+	_ = conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	})
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var c Command
+		err = json.Unmarshal(data, &c)
+		if err != nil {
+			watch.send(Message{Type: MessageError, Error: "invalid command: " + err.Error()})
+			continue
+		}
+		if !s.alive(id) {
+			watch.send(Message{Type: MessageError, Error: "game is not found or expired"})
+			return
+		}
+
+		err = handle(c)
+		if err != nil {
+			watch.send(Message{Type: MessageError, Error: err.Error()})
+		}
+	}
+}
+
+// writeLive sends queued messages and pings to client until watcher is closed or connection fails.
+// connection is closed when it returns
+func (s *Server) writeLive(conn *websocket.Conn, id string, watch *watcher) {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+	defer conn.Close()
+
+	for {
+		select {
+		case m := <-watch.out:
+			if writeMessage(conn, m) != nil {
+				return
+			}
+		case <-ticker.C:
+			// connected client keeps session alive, removed session closes connection
+			if !s.alive(id) {
+				closeLive(conn, websocket.CloseGoingAway, "game is not found or expired")
+				return
+			}
+			if conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)) != nil {
+				return
+			}
+		case <-watch.done:
+			// queued messages are delivered before connection is closed, e.g. error of the last command
+			for len(watch.out) > 0 {
+				if writeMessage(conn, <-watch.out) != nil {
+					return
+				}
+			}
+			closeLive(conn, websocket.CloseNormalClosure, "")
+			return
+		}
+	}
+}
+
+func writeMessage(conn *websocket.Conn, m Message) error {
+	err := conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err != nil {
+		return err
+	}
+
+	return conn.WriteJSON(m)
+}
+
+func closeLive(conn *websocket.Conn, code int, reason string) {
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(writeTimeout))
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/proxx/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dialLive connects to live channel of the game and reads the first state
+func dialLive(t *testing.T, srv *httptest.Server, id string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/games/" + id + "/live"
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	t.Cleanup(func() { conn.Close() })
+
+	m := readMessage(t, conn)
+	require.Equal(t, MessageState, m.Type)
+	require.Equal(t, id, m.State.ID)
+
+	return conn
+}
+
+func readMessage(t *testing.T, conn *websocket.Conn) Message {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var m Message
+	require.NoError(t, conn.ReadJSON(&m))
+
+	return m
+}
+
+// readUntilState reads messages till state of the game and returns types of events before it
+func readUntilState(t *testing.T, conn *websocket.Conn) ([]MessageType, *GameState) {
+	var types []MessageType
+	for {
+		m := readMessage(t, conn)
+		if m.Type == MessageState {
+			return types, m.State
+		}
+		types = append(types, m.Type)
+	}
+}
+
+func TestServer_Live(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	id := newGame(t, srv).ID
+	player := dialLive(t, srv, id)
+	// the second client only watches the game
+	watcher := dialLive(t, srv, id)
+
+	require.NoError(t, player.WriteJSON(Command{Action: "open", Row: 2, Col: 2}))
+	opened := []MessageType{"cellOpened", "cellOpened", "cellOpened", "cellOpened", "cellOpened", "cellOpened",
+		"cascadeFinished"}
+	for _, conn := range []*websocket.Conn{player, watcher} {
+		events, state := readUntilState(t, conn)
+		assert.Equal(t, opened, events)
+		assert.Equal(t, []string{"###", "111", "000"}, state.Cells)
+	}
+
+	// failed action is reported only to its sender
+	require.NoError(t, player.WriteJSON(Command{Action: "open", Row: 2, Col: 2}))
+	m := readMessage(t, player)
+	assert.Equal(t, Message{Type: MessageError, Error: game.ErrCellOpened.Error()}, m)
+	require.NoError(t, player.WriteJSON(Command{Action: "jump"}))
+	m = readMessage(t, player)
+	assert.Equal(t, Message{Type: MessageError, Error: `unknown action "jump"`}, m)
+
+	// actions made over HTTP API are pushed too
+	status := request(t, srv, http.MethodPost, "/api/games/"+id+"/flag", `{"row":0,"col":1}`, nil)
+	require.Equal(t, http.StatusOK, status)
+	for _, conn := range []*websocket.Conn{player, watcher} {
+		m := readMessage(t, conn)
+		assert.Equal(t, Message{
+			Type:  "flagToggled",
+			Event: map[string]interface{}{"row": 0.0, "col": 1.0, "flagged": true},
+		}, m)
+		_, state := readUntilState(t, conn)
+		assert.Equal(t, []string{"#F#", "111", "000"}, state.Cells)
+	}
+
+	require.NoError(t, player.WriteJSON(Command{Action: "open", Row: 0, Col: 0}))
+	_, _ = readUntilState(t, player)
+	require.NoError(t, player.WriteJSON(Command{Action: "chord", Row: 1, Col: 1}))
+	events, state := readUntilState(t, player)
+	assert.Equal(t, []MessageType{"cellOpened", "gameWon"}, events)
+	assert.Equal(t, game.State("win"), state.State)
+
+	// deleted game closes connection on the next command
+	require.Equal(t, http.StatusNoContent, request(t, srv, http.MethodDelete, "/api/games/"+id, "", nil))
+	require.NoError(t, player.WriteJSON(Command{Action: "undo"}))
+	m = readMessage(t, player)
+	assert.Equal(t, Message{Type: MessageError, Error: "game is not found or expired"}, m)
+	_, _, err := player.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), err)
+}
+
+func TestServer_LiveNotFound(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/games/123/live"
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,241 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/proxx/race"
+	"github.com/proxx/share"
+)
+
+// MessageRace has standings of the race, it is sent to players on every change of the race
+const MessageRace MessageType = "race"
+
+// RaceSettings describes new race: the board every player gets a copy of and number of players
+type RaceSettings struct {
+	Settings
+	Players int `json:"players"`
+}
+
+// RaceState is race as players see it
+type RaceState struct {
+	ID      string `json:"id"`
+	Profile string `json:"profile"`
+	// Code is code of the raced board, so it could be played again with start --code.
+	// it is given only when the race is finished, since the code tells where black holes are
+	Code string      `json:"code,omitempty"`
+	Race race.Update `json:"race"`
+}
+
+// raceRoom is a race held by the server
+type raceRoom struct {
+	id      string
+	profile string
+	code    string
+	race    *race.Race
+}
+
+func (room *raceRoom) state() RaceState {
+	state := RaceState{ID: room.id, Profile: room.profile, Race: room.race.Standings()}
+	if state.Race.Status == race.Finished {
+		state.Code = room.code
+	}
+
+	return state
+}
+
+// playerState returns state of the player's own game. ok is false before the race is started
+func (room *raceRoom) playerState(p *race.Player) (GameState, bool) {
+	snapshot, ok := p.Snapshot()
+	if !ok {
+		return GameState{}, false
+	}
+	stats, state, _ := p.Stats()
+
+	return newGameState(room.id, room.profile, state, snapshot, stats), true
+}
+
+// handleRaces creates new race: POST /api/races
+func (s *Server) handleRaces(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+	var settings RaceSettings
+	err := decodeBody(w, r, &settings)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	delay, err := settings.spectateDelay()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	room, err := newRaceRoom(settings)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	room.id, err = newSessionID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	err = s.add(room.id, room, delay)
+	if errors.Is(err, errTooManySessions) {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, room.state())
+}
+
+func newRaceRoom(settings RaceSettings) (*raceRoom, error) {
+	b, err := settings.board()
+	if err != nil {
+		return nil, err
+	}
+	rc, err := race.New(b, settings.Players)
+	if err != nil {
+		return nil, err
+	}
+	code, err := share.EncodeBoard(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &raceRoom{profile: b.Profile(), code: code, race: rc}, nil
+}
+
+// handleRace serves existing race:
+//
+//	GET /api/races/{id} returns standings of the race
+//	GET /api/races/{id}/live?player={name} joins the race over WebSocket, see handleRaceLive
+func (s *Server) handleRace(w http.ResponseWriter, r *http.Request) {
+	parts, ok := pathParts(r, racesPrefix)
+	if !ok || len(parts) == 2 && parts[1] != "live" {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+	v, _ := s.get(parts[0])
+	room, ok := v.(*raceRoom)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("race is not found or expired"))
+		return
+	}
+	if len(parts) == 1 {
+		writeJSON(w, http.StatusOK, room.state())
+		return
+	}
+	s.handleRaceLive(w, r, room)
+}
+
+// handleRaceLive joins player to the race. player receives standings of the race on every change and
+// own board when race starts and after every own move. commands are open, flag, chord and resign,
+// player who disconnects resigns
+func (s *Server) handleRaceLive(w http.ResponseWriter, r *http.Request, room *raceRoom) {
+	name := r.URL.Query().Get("player")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, errors.New("player name is required"))
+		return
+	}
+
+	watch := newWatcher()
+	// updates are delivered one by one, so started is not accessed concurrently
+	var started bool
+	unsubscribe := room.race.Subscribe(func(u race.Update) {
+		watch.send(Message{Type: MessageRace, Race: &u})
+		if started || u.Status == race.Waiting {
+			return
+		}
+		started = true
+		if p, ok := room.race.Player(name); ok {
+			room.sendState(watch, p)
+		}
+	})
+	defer unsubscribe()
+	p, err := room.race.Join(name)
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	defer p.Resign()
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// upgrader has already responded with error
+		return
+	}
+
+	s.serveLive(conn, room.id, watch, func(c Command) error {
+		var err error
+		switch c.Action {
+		case "open":
+			err = p.Open(c.Row, c.Col)
+		case "flag":
+			err = p.Flag(c.Row, c.Col)
+		case "chord":
+			err = p.Chord(c.Row, c.Col)
+		case "resign":
+			p.Resign()
+		default:
+			return fmt.Errorf("unknown race action %q", c.Action)
+		}
+		if err != nil {
+			return err
+		}
+		room.sendState(watch, p)
+
+		return nil
+	})
+}
+
+// sendState sends own game to the player
+func (room *raceRoom) sendState(watch *watcher, p *race.Player) {
+	if state, ok := room.playerState(p); ok {
+		watch.send(Message{Type: MessageState, State: &state})
+	}
+}
+
+// spectate sends standings of the race and boards of players on every change. boards of players who won or lost
+// show where black holes are, so they are sent only when the race is finished, since others play the same board
+func (room *raceRoom) spectate(watch *watcher) (stop func()) {
+	send := func(u race.Update) {
+		watch.send(Message{Type: MessageRace, Race: &u})
+		for _, standing := range u.Standings {
+			if u.Status == race.Running && (!standing.Alive || standing.Won) {
+				continue
+			}
+			p, ok := room.race.Player(standing.Name)
+			if !ok {
+				continue
+			}
+			if state, ok := room.playerState(p); ok {
+				watch.send(Message{Type: MessageState, Player: standing.Name, State: &state})
+			}
+		}
+	}
+	unsubscribe := room.race.Subscribe(send)
+	send(room.race.Standings())
+
+	return unsubscribe
+}
+
+func (room *raceRoom) info() SessionInfo {
+	u := room.race.Standings()
+	info := SessionInfo{Kind: kindRace, Profile: room.profile, State: string(u.Status)}
+	for _, standing := range u.Standings {
+		info.Players = append(info.Players, standing.Name)
+	}
+
+	return info
+}
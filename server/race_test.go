@@ -0,0 +1,149 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/proxx/race"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRace(t *testing.T, srv *httptest.Server, players int) RaceState {
+	var state RaceState
+	body := `{"code":"` + testCode(t) + `","players":` + strconv.Itoa(players) + `}`
+	require.Equal(t, http.StatusCreated, request(t, srv, http.MethodPost, "/api/races", body, &state))
+
+	return state
+}
+
+// joinRace connects player to the race
+func joinRace(t *testing.T, srv *httptest.Server, id, player string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/races/" + id + "/live?player=" + player
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// readRace reads messages till standings of the race
+func readRace(t *testing.T, conn *websocket.Conn) race.Update {
+	for {
+		m := readMessage(t, conn)
+		if m.Type == MessageRace {
+			return *m.Race
+		}
+	}
+}
+
+func TestServer_Race(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	created := newRace(t, srv, 2)
+	assert.Equal(t, "custom-3x3-1", created.Profile)
+	// code tells where black holes are, so it is hidden till the end of the race
+	assert.Empty(t, created.Code)
+	assert.Equal(t, race.Update{Status: race.Waiting, Players: 2, Standings: []race.Standing{}}, created.Race)
+
+	alice := joinRace(t, srv, created.ID, "alice")
+	u := readRace(t, alice)
+	assert.Equal(t, race.Waiting, u.Status)
+	assert.Len(t, u.Standings, 1)
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/races/" + created.ID + "/live?player=alice"
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	bob := joinRace(t, srv, created.ID, "bob")
+	var running RaceState
+	require.Equal(t, http.StatusOK, request(t, srv, http.MethodGet, "/api/races/"+created.ID, "", &running))
+	assert.Empty(t, running.Code)
+	// both players get own board when race starts
+	for _, conn := range []*websocket.Conn{alice, bob} {
+		m := readMessage(t, conn)
+		for m.Type != MessageState {
+			m = readMessage(t, conn)
+		}
+		assert.Equal(t, []string{"###", "###", "###"}, m.State.Cells)
+	}
+
+	require.NoError(t, alice.WriteJSON(Command{Action: "open", Row: 2, Col: 2}))
+	u = readRace(t, alice)
+	assert.Equal(t, race.Standing{Name: "alice", Place: 1, Progress: 0.75, Alive: true}, u.Standings[0])
+	_, state := readUntilState(t, alice)
+	assert.Equal(t, []string{"###", "111", "000"}, state.Cells)
+	assert.Equal(t, u, readRace(t, bob))
+
+	require.NoError(t, bob.WriteJSON(Command{Action: "undo"}))
+	m := readMessage(t, bob)
+	assert.Equal(t, Message{Type: MessageError, Error: `unknown race action "undo"`}, m)
+
+	// player who disconnects loses
+	require.NoError(t, bob.Close())
+	u = readRace(t, alice)
+	assert.Equal(t, race.Standing{Name: "bob", Place: 2, Time: u.Standings[1].Time}, u.Standings[1])
+
+	for _, c := range []Command{{Action: "flag", Row: 0, Col: 1}, {Action: "open"}, {Action: "chord", Row: 1, Col: 1}} {
+		require.NoError(t, alice.WriteJSON(c))
+		u = readRace(t, alice)
+	}
+	assert.Equal(t, race.Finished, u.Status)
+	assert.Equal(t, "alice", u.Standings[0].Name)
+	assert.True(t, u.Standings[0].Won)
+
+	var got RaceState
+	require.Equal(t, http.StatusOK, request(t, srv, http.MethodGet, "/api/races/"+created.ID, "", &got))
+	assert.Equal(t, u, got.Race)
+	assert.Equal(t, testCode(t), got.Code)
+}
+
+func TestServer_RaceErrors(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	id := newRace(t, srv, 2).ID
+
+	tests := []struct {
+		name, method, path, body string
+		wantStatus               int
+		wantErr                  string
+	}{
+		{name: "no_players", method: http.MethodPost, path: "/api/races", body: `{"preset":"beginner"}`,
+			wantStatus: http.StatusBadRequest, wantErr: "number of players must be from 1 to 16"},
+		{name: "infinite", method: http.MethodPost, path: "/api/races", body: `{"infinite":true,"players":2}`,
+			wantStatus: http.StatusBadRequest, wantErr: "infinite board can't be cleared, so it can't be raced"},
+		{name: "unknown_race", method: http.MethodGet, path: "/api/races/123",
+			wantStatus: http.StatusNotFound, wantErr: "race is not found or expired"},
+		{name: "game_is_not_race", method: http.MethodGet, path: "/api/races/" + newGame(t, srv).ID,
+			wantStatus: http.StatusNotFound, wantErr: "race is not found or expired"},
+		{name: "race_is_not_game", method: http.MethodGet, path: "/api/games/" + id,
+			wantStatus: http.StatusNotFound, wantErr: "game is not found or expired"},
+		{name: "no_player_name", method: http.MethodGet, path: "/api/races/" + id + "/live",
+			wantStatus: http.StatusBadRequest, wantErr: "player name is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got errorResponse
+			status := request(t, srv, tt.method, tt.path, tt.body, &got)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantErr, got.Error)
+		})
+	}
+}
+
+func TestServer_RaceIsNotDeletedAsGame(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	id := newRace(t, srv, 2).ID
+
+	assert.Equal(t, http.StatusNotFound, request(t, srv, http.MethodDelete, gamesPrefix+"/"+id, "", nil))
+	conn := joinRace(t, srv, id, "alice")
+	assert.Len(t, readRace(t, conn).Standings, 1)
+}
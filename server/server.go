@@ -0,0 +1,207 @@
+// Package server exposes game sessions over HTTP API, so the game could be played by other clients than terminal
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// defaults of the server
+const (
+	// DefaultTTL is time session is kept after the last request to it
+	DefaultTTL = 30 * time.Minute
+	// DefaultMaxSessions limits number of games held in memory
+	DefaultMaxSessions = 1000
+
+	gamesPrefix  = "/api/games"
+	racesPrefix  = "/api/races"
+	coopPrefix   = "/api/coop"
+	versusPrefix = "/api/versus"
+	watchPrefix  = "/api/watch"
+	listPath     = "/api/sessions"
+)
+
+// Server holds game sessions in memory and serves them over HTTP and WebSocket. sessions not used for ttl expire
+type Server struct {
+	mu sync.Mutex
+	// sessions are games, races, team games and versus matches by id
+	sessions    map[string]*entry
+	ttl         time.Duration
+	maxSessions int
+	// spectateDelay is the shortest delay of messages sent to spectators of any session
+	spectateDelay time.Duration
+	// now is replaced in tests to expire sessions without waiting
+	now      func() time.Time
+	mux      *http.ServeMux
+	upgrader websocket.Upgrader
+}
+
+// Option configures the server
+type Option func(s *Server)
+
+// WithTTL sets time session is kept after the last request to it
+func WithTTL(ttl time.Duration) Option {
+	return func(s *Server) {
+		s.ttl = ttl
+	}
+}
+
+// WithMaxSessions limits number of games held in memory
+func WithMaxSessions(n int) Option {
+	return func(s *Server) {
+		s.maxSessions = n
+	}
+}
+
+// WithSpectateDelay holds messages back from spectators of all sessions, so they can't help players.
+// sessions could set longer delay
+func WithSpectateDelay(delay time.Duration) Option {
+	return func(s *Server) {
+		s.spectateDelay = delay
+	}
+}
+
+// New creates server without sessions
+func New(opts ...Option) *Server {
+	s := &Server{
+		sessions:    make(map[string]*entry),
+		ttl:         DefaultTTL,
+		maxSessions: DefaultMaxSessions,
+		now:         time.Now,
+		mux:         http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.mux.HandleFunc(gamesPrefix, s.handleGames)
+	s.mux.HandleFunc(gamesPrefix+"/", s.handleGame)
+	s.mux.HandleFunc(racesPrefix, s.handleRaces)
+	s.mux.HandleFunc(racesPrefix+"/", s.handleRace)
+	s.mux.HandleFunc(coopPrefix, s.handleCoops)
+	s.mux.HandleFunc(coopPrefix+"/", s.handleCoop)
+	s.mux.HandleFunc(versusPrefix, s.handleVersuses)
+	s.mux.HandleFunc(versusPrefix+"/", s.handleVersus)
+	s.mux.HandleFunc(watchPrefix+"/", s.handleWatch)
+	s.mux.HandleFunc(listPath, s.handleSessions)
+	s.mux.Handle("/", frontend())
+
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// entry is game session, race, team game or versus match held by the server
+type entry struct {
+	value interface{}
+	// watchID is given to spectators instead of id, which lets players make moves
+	watchID string
+	// delay is delay of messages sent to spectators set by creator of the session
+	delay     time.Duration
+	expiresAt time.Time
+	createdAt time.Time
+	// spectators is number of spectators watching the session
+	spectators int
+}
+
+// add stores value under id made by newSessionID, spectators of it get messages with delay.
+// value must know its id before it is added, other requests can get it right after. expired entries
+// are dropped to make room for it
+func (s *Server) add(id string, value interface{}, delay time.Duration) error {
+	watchID, err := newSessionID()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for key, e := range s.sessions {
+		if now.After(e.expiresAt) {
+			delete(s.sessions, key)
+		}
+	}
+	if len(s.sessions) >= s.maxSessions {
+		return errTooManySessions
+	}
+	s.sessions[id] = &entry{value: value, watchID: watchID, delay: delay, expiresAt: now.Add(s.ttl), createdAt: now}
+
+	return nil
+}
+
+// get returns value by id and prolongs it. expired value is dropped and not found
+func (s *Server) get(id string) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.sessions[id]
+	if !ok {
+		return nil, false
+	}
+	now := s.now()
+	if now.After(e.expiresAt) {
+		delete(s.sessions, id)
+		return nil, false
+	}
+	e.expiresAt = now.Add(s.ttl)
+
+	return e.value, true
+}
+
+// alive reports whether value with id is still held, it prolongs the value
+func (s *Server) alive(id string) bool {
+	_, ok := s.get(id)
+	return ok
+}
+
+// watched returns id of the session by its spectator id and delay of messages sent to spectators of it
+func (s *Server) watched(watchID string) (id string, delay time.Duration, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, e := range s.sessions {
+		if e.watchID == watchID {
+			return id, e.delay, true
+		}
+	}
+
+	return "", 0, false
+}
+
+// session returns game session by id
+func (s *Server) session(id string) (*session, bool) {
+	v, _ := s.get(id)
+	sess, ok := v.(*session)
+
+	return sess, ok
+}
+
+// remove drops value, it reports whether value existed
+func (s *Server) remove(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.sessions[id]
+	delete(s.sessions, id)
+
+	return ok
+}
+
+// newSessionID returns random id which can't be guessed by other players
+func newSessionID() (string, error) {
+	var id [16]byte
+	_, err := rand.Read(id[:])
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id[:]), nil
+}
@@ -0,0 +1,231 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/proxx/game"
+	"github.com/proxx/share"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCode is code of 3x3 board with black hole at [0 1]
+func testCode(t *testing.T) string {
+	code, err := share.EncodeBoard(share.Board{Layout: &game.Layout{Rows: 3, Cols: 3, BlackHoles: [][]int{{0, 1}}}})
+	require.NoError(t, err)
+
+	return code
+}
+
+// request sends request to the server and decodes response to out if it is not nil
+func request(t *testing.T, srv *httptest.Server, method, path, body string, out interface{}) int {
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	resp, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+
+	return resp.StatusCode
+}
+
+func newGame(t *testing.T, srv *httptest.Server) GameState {
+	var state GameState
+	status := request(t, srv, http.MethodPost, "/api/games", `{"code":"`+testCode(t)+`"}`, &state)
+	require.Equal(t, http.StatusCreated, status)
+
+	return state
+}
+
+func TestServer_Play(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+
+	state := newGame(t, srv)
+	assert.NotEmpty(t, state.ID)
+	assert.Equal(t, "custom-3x3-1", state.Profile)
+	assert.Equal(t, game.State("inProgress"), state.State)
+	assert.Equal(t, []string{"###", "###", "###"}, state.Cells)
+	assert.Equal(t, 1, state.RemainingHoles)
+	path := "/api/games/" + state.ID
+
+	moves := []struct {
+		action, body string
+		wantStatus   int
+		wantCells    []string
+		wantState    game.State
+	}{
+		{action: "open", body: `{"row":2,"col":2}`, wantStatus: http.StatusOK, wantCells: []string{"###", "111", "000"}},
+		{action: "flag", body: `{"row":0,"col":1}`, wantStatus: http.StatusOK, wantCells: []string{"#F#", "111", "000"}},
+		{action: "open", body: `{"row":0,"col":1}`, wantStatus: http.StatusConflict},
+		{action: "chord", body: `{"row":0,"col":0}`, wantStatus: http.StatusConflict},
+		{action: "open", body: `{"row":0,"col":0}`, wantStatus: http.StatusOK, wantCells: []string{"1F#", "111", "000"}},
+		{action: "chord", body: `{"row":1,"col":1}`, wantStatus: http.StatusOK, wantCells: []string{"1F1", "111", "000"},
+			wantState: "win"},
+		{action: "open", body: `{"row":0,"col":1}`, wantStatus: http.StatusConflict},
+	}
+	for _, m := range moves {
+		var got GameState
+		status := request(t, srv, http.MethodPost, path+"/"+m.action, m.body, &got)
+		require.Equal(t, m.wantStatus, status, "%s %s", m.action, m.body)
+		if m.wantCells == nil {
+			continue
+		}
+		assert.Equal(t, m.wantCells, got.Cells, "%s %s", m.action, m.body)
+		if m.wantState != "" {
+			assert.Equal(t, m.wantState, got.State)
+		}
+	}
+
+	var got GameState
+	require.Equal(t, http.StatusOK, request(t, srv, http.MethodGet, path, "", &got))
+	assert.Equal(t, game.State("win"), got.State)
+	assert.Equal(t, 0, got.RemainingHoles)
+	assert.Equal(t, StatsState{
+		StartedAt:   got.Stats.StartedAt,
+		Duration:    got.Stats.Duration,
+		Moves:       4,
+		Clicks:      4,
+		CellsOpened: 8,
+		FlagsPlaced: 1,
+		Progress:    1,
+		Score:       got.Stats.Score,
+		BBBV:        3,
+		Efficiency:  0.75,
+	}, got.Stats)
+
+	require.Equal(t, http.StatusNoContent, request(t, srv, http.MethodDelete, path, "", nil))
+	assert.Equal(t, http.StatusNotFound, request(t, srv, http.MethodGet, path, "", nil))
+}
+
+func TestServer_UndoResign(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	path := "/api/games/" + newGame(t, srv).ID
+
+	var errResp errorResponse
+	require.Equal(t, http.StatusConflict, request(t, srv, http.MethodPost, path+"/undo", "", &errResp))
+	assert.Equal(t, game.ErrNothingToUndo.Error(), errResp.Error)
+
+	var state GameState
+	require.Equal(t, http.StatusOK, request(t, srv, http.MethodPost, path+"/open", `{"row":2,"col":2}`, &state))
+	assert.Equal(t, []string{"###", "111", "000"}, state.Cells)
+	require.Equal(t, http.StatusOK, request(t, srv, http.MethodPost, path+"/undo", "", &state))
+	assert.Equal(t, game.State("inProgress"), state.State)
+	assert.Equal(t, []string{"###", "###", "###"}, state.Cells)
+
+	require.Equal(t, http.StatusOK, request(t, srv, http.MethodPost, path+"/resign", "", &state))
+	assert.Equal(t, game.State("lose"), state.State)
+	// resigned game can't be undone
+	assert.Equal(t, http.StatusConflict, request(t, srv, http.MethodPost, path+"/undo", "", nil))
+	assert.Equal(t, http.StatusConflict, request(t, srv, http.MethodPost, path+"/resign", "", nil))
+}
+
+func TestServer_UndoLost(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	path := "/api/games/" + newGame(t, srv).ID
+
+	var state GameState
+	require.Equal(t, http.StatusOK, request(t, srv, http.MethodPost, path+"/open", `{"row":0,"col":1}`, &state))
+	assert.Equal(t, game.State("lose"), state.State)
+	assert.Equal(t, []string{"1*1", "111", "000"}, state.Cells)
+
+	// player has seen the black holes, so the game can't go on
+	var errResp errorResponse
+	require.Equal(t, http.StatusConflict, request(t, srv, http.MethodPost, path+"/undo", "", &errResp))
+	assert.Equal(t, "game is over: lost game can't be undone", errResp.Error)
+	require.Equal(t, http.StatusOK, request(t, srv, http.MethodGet, path, "", &state))
+	assert.Equal(t, game.State("lose"), state.State)
+}
+
+func TestServer_Errors(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	path := "/api/games/" + newGame(t, srv).ID
+	var created GameState
+	require.Equal(t, http.StatusCreated, request(t, srv, http.MethodPost, "/api/games", `{"infinite":true}`, &created))
+	infinite := "/api/games/" + created.ID
+
+	tests := []struct {
+		name, method, path, body string
+		wantStatus               int
+		wantErr                  string
+	}{
+		{name: "unknown_preset", method: http.MethodPost, path: "/api/games", body: `{"preset":"master"}`,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    `unknown preset "master", expected one of beginner, intermediate, expert`},
+		{name: "too_big_board", method: http.MethodPost, path: "/api/games", body: `{"size":1000,"blackHoles":1}`,
+			wantStatus: http.StatusBadRequest, wantErr: "board side must not be bigger than 200"},
+		{name: "empty_board", method: http.MethodPost, path: "/api/games", body: `{"size":0}`,
+			wantStatus: http.StatusBadRequest, wantErr: "board side must be positive"},
+		{name: "negative_board", method: http.MethodPost, path: "/api/games", body: `{"size":-3,"blackHoles":1}`,
+			wantStatus: http.StatusBadRequest, wantErr: "board side must be positive"},
+		{name: "negative_holes", method: http.MethodPost, path: "/api/games", body: `{"size":3,"blackHoles":-1}`,
+			wantStatus: http.StatusBadRequest, wantErr: "number of black holes must be from 0 to 8"},
+		{name: "only_holes", method: http.MethodPost, path: "/api/games", body: `{"size":3,"blackHoles":9}`,
+			wantStatus: http.StatusBadRequest, wantErr: "number of black holes must be from 0 to 8"},
+		{name: "out_of_infinite_area", method: http.MethodPost, path: infinite + "/open", body: `{"row":0,"col":101}`,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "click coordinate [0 101] is out of board area, coordinates must be from -100 to 100"},
+		{name: "long_spectate_delay", method: http.MethodPost, path: "/api/games",
+			body: `{"size":3,"blackHoles":1,"spectateDelay":"1h"}`, wantStatus: http.StatusBadRequest,
+			wantErr: "delay must be duration from 0 to 10m0s"},
+		{name: "invalid_code", method: http.MethodPost, path: "/api/games", body: `{"code":"???"}`,
+			wantStatus: http.StatusBadRequest, wantErr: share.ErrInvalidCode.Error()},
+		{name: "unknown_field", method: http.MethodPost, path: "/api/games", body: `{"side":3}`,
+			wantStatus: http.StatusBadRequest, wantErr: `invalid request body: json: unknown field "side"`},
+		{name: "list_games", method: http.MethodGet, path: "/api/games",
+			wantStatus: http.StatusMethodNotAllowed, wantErr: "method GET is not allowed"},
+		{name: "unknown_game", method: http.MethodGet, path: "/api/games/123",
+			wantStatus: http.StatusNotFound, wantErr: "game is not found or expired"},
+		{name: "unknown_action", method: http.MethodPost, path: path + "/jump", body: `{}`,
+			wantStatus: http.StatusNotFound, wantErr: `unknown action "jump"`},
+		{name: "get_action", method: http.MethodGet, path: path + "/open",
+			wantStatus: http.StatusMethodNotAllowed, wantErr: "method GET is not allowed"},
+		{name: "empty_move", method: http.MethodPost, path: path + "/open",
+			wantStatus: http.StatusBadRequest, wantErr: "invalid request body: EOF"},
+		{name: "out_of_bounds", method: http.MethodPost, path: path + "/flag", body: `{"row":3,"col":0}`,
+			wantStatus: http.StatusBadRequest, wantErr: "click coordinate [3 0] is out of board bounds 3 x 3"},
+		{name: "chord_closed_cell", method: http.MethodPost, path: path + "/chord", body: `{"row":0,"col":0}`,
+			wantStatus: http.StatusConflict, wantErr: "cell can't be chorded: only opened cell with number could be chorded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got errorResponse
+			status := request(t, srv, tt.method, tt.path, tt.body, &got)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantErr, got.Error)
+		})
+	}
+}
+
+func TestServer_Expiry(t *testing.T) {
+	now := time.Now()
+	s := New(WithTTL(time.Minute), WithMaxSessions(1))
+	s.now = func() time.Time { return now }
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	path := "/api/games/" + newGame(t, srv).ID
+	body := `{"code":"` + testCode(t) + `"}`
+	assert.Equal(t, http.StatusServiceUnavailable, request(t, srv, http.MethodPost, "/api/games", body, nil))
+
+	// every request prolongs the session
+	now = now.Add(50 * time.Second)
+	require.Equal(t, http.StatusOK, request(t, srv, http.MethodGet, path, "", nil))
+	now = now.Add(50 * time.Second)
+	require.Equal(t, http.StatusOK, request(t, srv, http.MethodGet, path, "", nil))
+
+	// expired session is dropped and doesn't take room of new ones
+	now = now.Add(time.Minute + time.Second)
+	assert.Equal(t, http.StatusCreated, request(t, srv, http.MethodPost, "/api/games", body, nil))
+	assert.Equal(t, http.StatusNotFound, request(t, srv, http.MethodGet, path, "", nil))
+}
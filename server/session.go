@@ -0,0 +1,290 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/proxx/game"
+	"github.com/proxx/share"
+)
+
+const (
+	// maxSide limits size of boards created by clients, so a single request can't take all the memory.
+	// boards shared by codes are limited the same way
+	maxSide = share.MaxSide
+	// maxReach limits coordinates of cells opened on infinite boards, so they can't take more memory
+	// than the biggest board of fixed size
+	maxReach = maxSide / 2
+)
+
+var errTooManySessions = errors.New("too many games are played, try again later")
+
+// Settings describes new game. board is set by code, preset, infinite flag or size and number of black holes
+type Settings struct {
+	// Code is board code printed by share command
+	Code   string `json:"code,omitempty"`
+	Preset string `json:"preset,omitempty"`
+	// Size and BlackHoles set custom board of fixed size
+	Size       int `json:"size,omitempty"`
+	BlackHoles int `json:"blackHoles,omitempty"`
+	// Seed generates the same board every time, random seed is used if it is not set
+	Seed     *int64  `json:"seed,omitempty"`
+	Infinite bool    `json:"infinite,omitempty"`
+	Density  float64 `json:"density,omitempty"`
+	// SpectateDelay holds messages back from spectators, e.g. "30s", so they can't help players.
+	// spectators can ask for longer delay but not for shorter one
+	SpectateDelay string `json:"spectateDelay,omitempty"`
+}
+
+// defaultDensity is density of infinite board when it is not set
+const defaultDensity = 0.15
+
+// board describes board set by settings
+func (s Settings) board() (share.Board, error) {
+	if s.Code != "" {
+		b, err := share.DecodeBoard(s.Code)
+		if err != nil {
+			return share.Board{}, err
+		}
+		if !b.Infinite && b.Layout == nil {
+			err = checkSize(b.Size, b.BlackHoles)
+			if err != nil {
+				return share.Board{}, err
+			}
+		}
+
+		return b, nil
+	}
+
+	seed := time.Now().UnixNano()
+	if s.Seed != nil {
+		seed = *s.Seed
+	}
+	if s.Infinite {
+		density := s.Density
+		if density == 0 {
+			density = defaultDensity
+		}
+
+		return share.Board{Infinite: true, Seed: seed, Density: density}, nil
+	}
+	size, blackHoles := s.Size, s.BlackHoles
+	if s.Preset != "" {
+		p, err := game.PresetByName(s.Preset)
+		if err != nil {
+			return share.Board{}, err
+		}
+		size, blackHoles = p.Size, p.BlackHoles
+	}
+	err := checkSize(size, blackHoles)
+	if err != nil {
+		return share.Board{}, err
+	}
+
+	return share.Board{Size: size, BlackHoles: blackHoles, Seed: seed}, nil
+}
+
+// spectateDelay returns delay of messages sent to spectators of the session
+func (s Settings) spectateDelay() (time.Duration, error) {
+	if s.SpectateDelay == "" {
+		return 0, nil
+	}
+
+	return parseDelay(s.SpectateDelay)
+}
+
+// checkSize checks board of fixed size set by client, at least one cell has to be safe
+func checkSize(size, blackHoles int) error {
+	switch {
+	case size < 1:
+		return errors.New("board side must be positive")
+	case size > maxSide:
+		return fmt.Errorf("board side must not be bigger than %d", maxSide)
+	case blackHoles < 0 || blackHoles >= size*size:
+		return fmt.Errorf("number of black holes must be from 0 to %d", size*size-1)
+	}
+
+	return nil
+}
+
+// newBoard creates board described by settings, infinite board is limited to maxReach cells around zero
+func newBoard(shared share.Board) (*game.Board, error) {
+	b, err := shared.NewBoard()
+	if err != nil {
+		return nil, err
+	}
+	if shared.Infinite {
+		err = b.LimitArea(maxReach)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// actions are moves player makes in the game. Move is used only by actions that move cell, see movesCell
+var actions = map[string]func(g *game.Game, m Move) error{
+	"open":   func(g *game.Game, m Move) error { return g.Open(m.Row, m.Col) },
+	"flag":   func(g *game.Game, m Move) error { return g.Flag(m.Row, m.Col) },
+	"chord":  func(g *game.Game, m Move) error { return g.Chord(m.Row, m.Col) },
+	"undo":   func(g *game.Game, _ Move) error { return g.Undo() },
+	"resign": func(g *game.Game, _ Move) error { return g.Resign() },
+}
+
+// movesCell reports whether action is made on the cell
+func movesCell(action string) bool {
+	return action == "open" || action == "flag" || action == "chord"
+}
+
+// session is a game played over API. game is not safe for concurrent use, so requests to it are serialized
+type session struct {
+	mu      sync.Mutex
+	game    *game.Game
+	board   *game.Board
+	profile string
+	// watchers are live connections to the game, they get new state after every action
+	watchers map[*watcher]struct{}
+	// id is set once before session is stored
+	id string
+}
+
+// newSession creates game by settings
+func newSession(settings Settings) (*session, error) {
+	shared, err := settings.board()
+	if err != nil {
+		return nil, err
+	}
+	b, err := newBoard(shared)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session{
+		game:     game.NewGame(b),
+		board:    b,
+		profile:  shared.Profile(),
+		watchers: make(map[*watcher]struct{}),
+	}, nil
+}
+
+// do makes action in the game and sends new state to watchers, session must be locked
+func (s *session) do(action string, m Move) error {
+	fn, ok := actions[action]
+	if !ok {
+		return fmt.Errorf("unknown action %q", action)
+	}
+	// lost board is sent to player with all black holes, so going back to it would reveal them
+	if action == "undo" && s.board.State() == game.BoardBlackHoled {
+		return fmt.Errorf("%w: lost game can't be undone", game.ErrGameOver)
+	}
+	err := fn(s.game, m)
+	if err != nil {
+		return err
+	}
+	if len(s.watchers) > 0 {
+		state := s.state()
+		for w := range s.watchers {
+			w.send(Message{Type: MessageState, State: &state})
+		}
+	}
+
+	return nil
+}
+
+// spectate subscribes watcher to board events and state of the game. watcher is subscribed under session lock,
+// so no event is missed or duplicated in the first state
+func (s *session) spectate(watch *watcher) (stop func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unsubscribe := s.board.Subscribe(func(e game.Event) {
+		watch.send(eventMessage(e))
+	})
+	s.watchers[watch] = struct{}{}
+	state := s.state()
+	watch.send(Message{Type: MessageState, State: &state})
+
+	return func() {
+		s.mu.Lock()
+		delete(s.watchers, watch)
+		s.mu.Unlock()
+		unsubscribe()
+	}
+}
+
+func (s *session) info() SessionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return SessionInfo{Kind: kindGame, Profile: s.profile, State: string(s.game.GetState())}
+}
+
+// GameState is game as player sees it
+type GameState struct {
+	ID      string     `json:"id"`
+	Profile string     `json:"profile"`
+	State   game.State `json:"state"`
+	// Top and Left are coordinates of the first cell, they are not zero only for infinite board
+	Top  int `json:"top"`
+	Left int `json:"left"`
+	Rows int `json:"rows"`
+	Cols int `json:"cols"`
+	// RemainingHoles is number of black holes minus number of flags, it is -1 for infinite board
+	RemainingHoles int `json:"remainingHoles"`
+	Flags          int `json:"flags"`
+	// Cells has row by row symbols of cells: # closed, F flagged, 0-8 opened, * black hole
+	Cells []string   `json:"cells"`
+	Stats StatsState `json:"stats"`
+}
+
+// StatsState is statistics of the game
+type StatsState struct {
+	StartedAt   time.Time     `json:"startedAt"`
+	Duration    time.Duration `json:"duration"`
+	Moves       int           `json:"moves"`
+	Clicks      int           `json:"clicks"`
+	CellsOpened int           `json:"cellsOpened"`
+	FlagsPlaced int           `json:"flagsPlaced"`
+	UndosUsed   int           `json:"undosUsed"`
+	Progress    float64       `json:"progress"`
+	Score       int           `json:"score"`
+	BBBV        int           `json:"bbbv,omitempty"`
+	Efficiency  float64       `json:"efficiency,omitempty"`
+}
+
+// state returns state of the game, session must be locked
+func (s *session) state() GameState {
+	return newGameState(s.id, s.profile, s.game.GetState(), s.board.Snapshot(), s.game.Stats())
+}
+
+// newGameState describes game as player sees it
+func newGameState(id, profile string, gameState game.State, snapshot game.Snapshot, stats game.Stats) GameState {
+	return GameState{
+		ID:             id,
+		Profile:        profile,
+		State:          gameState,
+		Top:            snapshot.Top,
+		Left:           snapshot.Left,
+		Rows:           snapshot.Rows,
+		Cols:           snapshot.Cols,
+		RemainingHoles: snapshot.RemainingHoles,
+		Flags:          snapshot.Flags,
+		Cells:          snapshot.Symbols(),
+		Stats: StatsState{
+			StartedAt:   stats.StartedAt,
+			Duration:    stats.Duration(),
+			Moves:       stats.Moves,
+			Clicks:      stats.Clicks,
+			CellsOpened: stats.CellsOpened,
+			FlagsPlaced: stats.FlagsPlaced,
+			UndosUsed:   stats.UndosUsed,
+			Progress:    stats.Progress,
+			Score:       stats.Score,
+			BBBV:        stats.BBBV,
+			Efficiency:  stats.Efficiency,
+		},
+	}
+}
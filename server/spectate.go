@@ -0,0 +1,249 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxSpectateDelay limits delay of messages sent to spectators
+	MaxSpectateDelay = 10 * time.Minute
+	// delayBuffer limits number of messages held back for delayed spectator, slower spectator is disconnected
+	delayBuffer = 4096
+)
+
+// errSpectator is returned on commands sent by spectator
+var errSpectator = errors.New("spectators can't make moves")
+
+// list of session kinds
+const (
+	kindGame   = "game"
+	kindRace   = "race"
+	kindCoop   = "coop"
+	kindVersus = "versus"
+)
+
+// SessionInfo describes active session in the list of games
+type SessionInfo struct {
+	// WatchID is id of the session for /api/watch, it can't be used to make moves
+	WatchID string `json:"watchId"`
+	// Kind is game, race, coop or versus
+	Kind    string `json:"kind"`
+	Profile string `json:"profile"`
+	// State is state of the game or status of the race or match, e.g. inProgress or waiting
+	State      string    `json:"state"`
+	Players    []string  `json:"players,omitempty"`
+	Spectators int       `json:"spectators"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// spectated is implemented by sessions which can be watched
+type spectated interface {
+	info() SessionInfo
+	// spectate sends current state of the session to watcher and then every change of it until stop is called.
+	// spectators never get black holes hidden from players
+	spectate(watch *watcher) (stop func())
+}
+
+// handleSessions lists active sessions: GET /api/sessions
+func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.list())
+}
+
+// list returns active sessions ordered by creation time, listing doesn't prolong them
+func (s *Server) list() []SessionInfo {
+	type listed struct {
+		value      spectated
+		watchID    string
+		spectators int
+		createdAt  time.Time
+	}
+
+	s.mu.Lock()
+	now := s.now()
+	all := make([]listed, 0, len(s.sessions))
+	for _, e := range s.sessions {
+		v, ok := e.value.(spectated)
+		if ok && !now.After(e.expiresAt) {
+			all = append(all, listed{value: v, watchID: e.watchID, spectators: e.spectators, createdAt: e.createdAt})
+		}
+	}
+	s.mu.Unlock()
+
+	// sessions are locked by info, so it is called when server is unlocked
+	infos := make([]SessionInfo, 0, len(all))
+	for _, l := range all {
+		info := l.value.info()
+		info.WatchID = l.watchID
+		info.Spectators = l.spectators
+		info.CreatedAt = l.createdAt
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if !infos[i].CreatedAt.Equal(infos[j].CreatedAt) {
+			return infos[i].CreatedAt.Before(infos[j].CreatedAt)
+		}
+		return infos[i].WatchID < infos[j].WatchID
+	})
+
+	return infos
+}
+
+// spectators changes number of spectators of the session
+func (s *Server) spectators(id string, delta int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.sessions[id]; ok {
+		e.spectators += delta
+	}
+}
+
+// handleWatch attaches spectator to the session: GET /api/watch/{watchId}?delay=30s upgraded to WebSocket.
+// spectator receives the same messages as players and can't send commands. messages are delayed by the longest
+// of delays set by the server, creator of the session and spectator
+func (s *Server) handleWatch(w http.ResponseWriter, r *http.Request) {
+	watchID := strings.TrimPrefix(r.URL.Path, watchPrefix+"/")
+	if watchID == "" || strings.Contains(watchID, "/") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+	var asked time.Duration
+	if v := r.URL.Query().Get("delay"); v != "" {
+		var err error
+		asked, err = parseDelay(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	// spectators know only watch id, the session id is needed for bookkeeping
+	id, delay, _ := s.watched(watchID)
+	// spectator can make delay set by the server or creator of the session only longer
+	delay = maxDuration(delay, maxDuration(s.spectateDelay, asked))
+	v, _ := s.get(id)
+	watched, ok := v.(spectated)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("game is not found or expired"))
+		return
+	}
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// upgrader has already responded with error
+		return
+	}
+
+	watch := newWatcher()
+	in := watch
+	if delay > 0 {
+		in = delayed(watch, delay)
+	}
+	in.spectator = true
+	stop := watched.spectate(in)
+	defer stop()
+	s.spectators(id, 1)
+	defer s.spectators(id, -1)
+
+	s.serveLive(conn, id, watch, func(Command) error {
+		return errSpectator
+	})
+}
+
+// parseDelay parses delay of messages sent to spectators
+func parseDelay(v string) (time.Duration, error) {
+	delay, err := time.ParseDuration(v)
+	if err != nil || delay < 0 || delay > MaxSpectateDelay {
+		return 0, fmt.Errorf("delay must be duration from 0 to %v", MaxSpectateDelay)
+	}
+
+	return delay, nil
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+// withoutID returns copy of message without id of the session. message is shared by all watchers, so it isn't changed
+func withoutID(m Message) Message {
+	if m.State != nil {
+		state := *m.State
+		state.ID = ""
+		m.State = &state
+	}
+	if m.Versus != nil {
+		state := *m.Versus
+		state.ID = ""
+		m.Versus = &state
+	}
+
+	return m
+}
+
+// delayed returns watcher which passes messages to out after delay, so spectators can't tell players
+// what is happening in competitive games. it stops when out is closed
+func delayed(out *watcher, delay time.Duration) *watcher {
+	type held struct {
+		m  Message
+		at time.Time
+	}
+
+	in := newWatcher()
+	go func() {
+		defer in.close()
+
+		var queue []held
+		timer := time.NewTimer(delay)
+		if !timer.Stop() {
+			<-timer.C
+		}
+		defer timer.Stop()
+		// due is set while timer is running for the first held message
+		var due <-chan time.Time
+		for {
+			select {
+			case <-out.done:
+				return
+			case <-in.done:
+				out.close()
+				return
+			case m := <-in.out:
+				if len(queue) >= delayBuffer {
+					out.close()
+					return
+				}
+				queue = append(queue, held{m: m, at: time.Now().Add(delay)})
+			case <-due:
+				due = nil
+			}
+
+			now := time.Now()
+			for len(queue) > 0 && !queue[0].at.After(now) {
+				out.send(queue[0].m)
+				queue = queue[1:]
+			}
+			if due == nil && len(queue) > 0 {
+				timer.Reset(queue[0].at.Sub(now))
+				due = timer.C
+			}
+		}
+	}()
+
+	return in
+}
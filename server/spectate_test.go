@@ -0,0 +1,249 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/proxx/race"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sessions returns listed sessions
+func sessions(t *testing.T, srv *httptest.Server) []SessionInfo {
+	var list []SessionInfo
+	require.Equal(t, http.StatusOK, request(t, srv, http.MethodGet, "/api/sessions", "", &list))
+
+	return list
+}
+
+// watchID returns spectator id of the only listed session
+func watchID(t *testing.T, srv *httptest.Server) string {
+	list := sessions(t, srv)
+	require.Len(t, list, 1)
+
+	return list[0].WatchID
+}
+
+// watch connects spectator to the only session
+func watch(t *testing.T, srv *httptest.Server, query string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/watch/" + watchID(t, srv) + query
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// assertCantMove checks that id of the session received by spectator doesn't let make moves in it
+func assertCantMove(t *testing.T, srv *httptest.Server, prefix, id string) {
+	t.Helper()
+	assert.Empty(t, id)
+	if prefix == gamesPrefix {
+		path := prefix + "/" + id + "/open"
+		assert.Equal(t, http.StatusNotFound, request(t, srv, http.MethodPost, path, `{"row":0,"col":0}`, nil))
+		return
+	}
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + prefix + "/" + id + "/live?player=spy"
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	resp.Body.Close()
+}
+
+func TestServer_Watch(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	id := newGame(t, srv).ID
+	spectator := watch(t, srv, "")
+	m := readMessage(t, spectator)
+	require.Equal(t, MessageState, m.Type)
+	assert.Equal(t, []string{"###", "###", "###"}, m.State.Cells)
+	assertCantMove(t, srv, gamesPrefix, m.State.ID)
+
+	list := sessions(t, srv)
+	require.Len(t, list, 1)
+	assert.NotEqual(t, id, list[0].WatchID)
+	assert.Equal(t, "game", list[0].Kind)
+	assert.Equal(t, "inProgress", list[0].State)
+	assert.Equal(t, 1, list[0].Spectators)
+
+	require.Equal(t, http.StatusOK, request(t, srv, http.MethodPost, "/api/games/"+id+"/open", `{"row":2,"col":2}`, nil))
+	events, state := readUntilState(t, spectator)
+	assert.Len(t, events, 7)
+	assert.Equal(t, []string{"###", "111", "000"}, state.Cells)
+	assertCantMove(t, srv, gamesPrefix, state.ID)
+
+	// spectator can only watch
+	require.NoError(t, spectator.WriteJSON(Command{Action: "open", Row: 0, Col: 0}))
+	m = readMessage(t, spectator)
+	assert.Equal(t, Message{Type: MessageError, Error: errSpectator.Error()}, m)
+}
+
+func TestServer_WatchDelay(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	id := newGame(t, srv).ID
+	const delay = 200 * time.Millisecond
+
+	started := time.Now()
+	spectator := watch(t, srv, "?delay=200ms")
+	require.Equal(t, http.StatusOK, request(t, srv, http.MethodPost, "/api/games/"+id+"/flag", `{"row":0,"col":1}`, nil))
+	m := readMessage(t, spectator)
+	assert.Equal(t, MessageState, m.Type)
+	assert.GreaterOrEqual(t, time.Since(started), delay)
+	// messages keep their order
+	m = readMessage(t, spectator)
+	assert.Equal(t, MessageType("flagToggled"), m.Type)
+	_, state := readUntilState(t, spectator)
+	assert.Equal(t, []string{"#F#", "###", "###"}, state.Cells)
+}
+
+func TestServer_WatchDelayNotLowered(t *testing.T) {
+	const delay = 200 * time.Millisecond
+	tests := []struct {
+		name     string
+		opts     []Option
+		settings string
+	}{
+		{name: "set_by_server", opts: []Option{WithSpectateDelay(delay)}, settings: `{"size":3,"blackHoles":1}`},
+		{name: "set_by_creator", settings: `{"size":3,"blackHoles":1,"spectateDelay":"200ms"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(New(tt.opts...))
+			defer srv.Close()
+			var created GameState
+			require.Equal(t, http.StatusCreated, request(t, srv, http.MethodPost, "/api/games", tt.settings, &created))
+
+			// spectator asking for shorter delay gets the one of the session
+			started := time.Now()
+			spectator := watch(t, srv, "?delay=0s")
+			m := readMessage(t, spectator)
+			assert.Equal(t, MessageState, m.Type)
+			assert.GreaterOrEqual(t, time.Since(started), delay)
+		})
+	}
+}
+
+func TestServer_WatchRace(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	id := newRace(t, srv, 2).ID
+	alice := joinRace(t, srv, id, "alice")
+	bob := joinRace(t, srv, id, "bob")
+	_ = readRace(t, alice)
+
+	spectator := watch(t, srv, "")
+	u := readRace(t, spectator)
+	require.Equal(t, race.Running, u.Status)
+	boards := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		m := readMessage(t, spectator)
+		require.Equal(t, MessageState, m.Type)
+		boards[m.Player] = true
+		assertCantMove(t, srv, racesPrefix, m.State.ID)
+	}
+	assert.Equal(t, map[string]bool{"alice": true, "bob": true}, boards)
+
+	// board of lost alice shows black holes, so it is not sent while bob plays the same board
+	require.NoError(t, alice.WriteJSON(Command{Action: "open", Row: 0, Col: 1}))
+	u = readRace(t, spectator)
+	assert.Equal(t, race.Running, u.Status)
+	m := readMessage(t, spectator)
+	assert.Equal(t, "bob", m.Player)
+	assert.Equal(t, []string{"###", "###", "###"}, m.State.Cells)
+
+	for _, move := range [][]int{{2, 2}, {0, 0}} {
+		require.NoError(t, bob.WriteJSON(Command{Action: "open", Row: move[0], Col: move[1]}))
+		// the next standings follow bob's board right away
+		assert.Equal(t, MessageRace, readMessage(t, spectator).Type)
+		m = readMessage(t, spectator)
+		assert.Equal(t, "bob", m.Player)
+	}
+	require.NoError(t, bob.WriteJSON(Command{Action: "open", Row: 0, Col: 2}))
+	u = readRace(t, spectator)
+	assert.Equal(t, race.Finished, u.Status)
+	cells := map[string][]string{}
+	for i := 0; i < 2; i++ {
+		m = readMessage(t, spectator)
+		cells[m.Player] = m.State.Cells
+	}
+	assert.Equal(t, []string{"1*1", "111", "000"}, cells["alice"])
+}
+
+func TestServer_WatchCoop(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	var created CoopState
+	body := `{"code":"` + testCode(t) + `","mode":"free"}`
+	require.Equal(t, http.StatusCreated, request(t, srv, http.MethodPost, "/api/coop", body, &created))
+	alice := joinTeam(t, srv, created.ID, "alice")
+
+	spectator := watch(t, srv, "")
+	_, state := readUntilState(t, spectator)
+	assert.Equal(t, []string{"###", "###", "###"}, state.Cells)
+	assertCantMove(t, srv, coopPrefix, state.ID)
+
+	require.NoError(t, alice.WriteJSON(Command{Action: "open", Row: 2, Col: 2}))
+	events, status, state := readMove(t, spectator)
+	assert.Contains(t, events, "alice cellOpened")
+	assert.Len(t, status.Players, 1)
+	assert.Equal(t, []string{"###", "111", "000"}, state.Cells)
+	assertCantMove(t, srv, coopPrefix, state.ID)
+}
+
+func TestServer_WatchErrors(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	_ = newGame(t, srv)
+	id := watchID(t, srv)
+
+	tests := []struct {
+		name, path string
+		wantStatus int
+		wantErr    string
+	}{
+		{name: "unknown", path: "/api/watch/123", wantStatus: http.StatusNotFound, wantErr: "game is not found or expired"},
+		{name: "nested", path: "/api/watch/" + id + "/live", wantStatus: http.StatusNotFound, wantErr: "not found"},
+		{name: "bad_delay", path: "/api/watch/" + id + "?delay=soon", wantStatus: http.StatusBadRequest,
+			wantErr: "delay must be duration from 0 to 10m0s"},
+		{name: "long_delay", path: "/api/watch/" + id + "?delay=1h", wantStatus: http.StatusBadRequest,
+			wantErr: "delay must be duration from 0 to 10m0s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp errorResponse
+			assert.Equal(t, tt.wantStatus, request(t, srv, http.MethodGet, tt.path, "", &resp))
+			assert.Equal(t, tt.wantErr, resp.Error)
+		})
+	}
+}
+
+func TestServer_WatchIDCantMove(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	_ = newGame(t, srv)
+	id := watchID(t, srv)
+
+	for _, action := range []string{"open", "flag", "chord", "undo", "resign"} {
+		var resp errorResponse
+		path := "/api/games/" + id + "/" + action
+		assert.Equal(t, http.StatusNotFound, request(t, srv, http.MethodPost, path, `{"row":0,"col":0}`, &resp), action)
+	}
+	assert.Equal(t, http.StatusNotFound, request(t, srv, http.MethodDelete, "/api/games/"+id, "", nil))
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/games/" + id + "/live"
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// the game is still untouched
+	m := readMessage(t, watch(t, srv, ""))
+	assert.Equal(t, []string{"###", "###", "###"}, m.State.Cells)
+}
@@ -0,0 +1,369 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/proxx/game"
+	"github.com/proxx/share"
+	"github.com/proxx/versus"
+)
+
+// MessageVersus has state of the versus match, it is sent on every join and after every move
+const MessageVersus MessageType = "versus"
+
+// VersusSettings describes new versus match: the board, number of players and rule of black holes
+type VersusSettings struct {
+	Settings
+	Players int    `json:"players"`
+	Rule    string `json:"rule,omitempty"`
+}
+
+// VersusState is versus match as players see it
+type VersusState struct {
+	ID      string `json:"id"`
+	Profile string `json:"profile"`
+	// Players is number of players match starts with
+	Players int      `json:"players"`
+	Joined  []string `json:"joined"`
+	// Match is nil until all players have joined
+	Match *versus.Status `json:"match,omitempty"`
+}
+
+// versusRoom is a versus match held by the server. players wait in the room until it is full
+type versusRoom struct {
+	id      string
+	profile string
+	shared  share.Board
+	rule    versus.Rule
+	players int
+
+	// mu serializes joins and moves, match events are delivered while room is locked by the move
+	mu       sync.Mutex
+	joined   []string
+	watchers map[string]*watcher
+	// spectators get the same messages as players
+	spectators map[*watcher]struct{}
+	match      *versus.Match
+	game       *game.Game
+}
+
+func newVersusRoom(settings VersusSettings) (*versusRoom, error) {
+	rule, err := versus.ParseRule(settings.Rule)
+	if err != nil {
+		return nil, err
+	}
+	if settings.Players < 2 || settings.Players > versus.MaxPlayers {
+		return nil, fmt.Errorf("number of players must be from 2 to %d", versus.MaxPlayers)
+	}
+	shared, err := settings.board()
+	if err != nil {
+		return nil, err
+	}
+	if shared.Infinite {
+		return nil, errors.New("versus match can't be played on infinite board")
+	}
+	// board is created right away, so invalid settings are reported before anybody joins
+	_, err = shared.NewBoard()
+	if err != nil {
+		return nil, err
+	}
+
+	return &versusRoom{
+		profile:    shared.Profile(),
+		shared:     shared,
+		rule:       rule,
+		players:    settings.Players,
+		watchers:   make(map[string]*watcher),
+		spectators: make(map[*watcher]struct{}),
+	}, nil
+}
+
+func (room *versusRoom) state() VersusState {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	return room.lockedState()
+}
+
+// lockedState returns state of the match, room must be locked
+func (room *versusRoom) lockedState() VersusState {
+	s := VersusState{ID: room.id, Profile: room.profile, Players: room.players,
+		Joined: append([]string(nil), room.joined...)}
+	if room.match != nil {
+		status := room.match.Status()
+		s.Match = &status
+	}
+
+	return s
+}
+
+// join adds player to the room and starts the match when room is full
+func (room *versusRoom) join(name string, watch *watcher) error {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	switch {
+	case room.watchers[name] != nil:
+		return errors.New("player with this name has already joined")
+	case len(room.joined) >= room.players:
+		return errors.New("match is full")
+	}
+	room.joined = append(room.joined, name)
+	room.watchers[name] = watch
+	if len(room.joined) == room.players {
+		err := room.start()
+		if err != nil {
+			room.joined = room.joined[:len(room.joined)-1]
+			delete(room.watchers, name)
+			return err
+		}
+	}
+	room.broadcast()
+
+	return nil
+}
+
+// start creates the match, players move in order they joined
+func (room *versusRoom) start() error {
+	b, err := room.shared.NewBoard()
+	if err != nil {
+		return err
+	}
+	room.match, err = versus.New(b, room.rule, room.joined...)
+	if err != nil {
+		return err
+	}
+	room.game = game.NewGame(room.match)
+	room.match.Subscribe(room.onMatchEvent)
+
+	return nil
+}
+
+// leave forfeits the match of disconnected player
+func (room *versusRoom) leave(name string) {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	delete(room.watchers, name)
+	if room.match == nil {
+		for i, joined := range room.joined {
+			if joined == name {
+				room.joined = append(room.joined[:i:i], room.joined[i+1:]...)
+				break
+			}
+		}
+		room.broadcast()
+		return
+	}
+	room.match.Forfeit(name)
+	room.broadcast()
+}
+
+// move makes move of the player, moves are accepted only from the player whose turn it is
+func (room *versusRoom) move(name string, c Command) error {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	if room.match == nil {
+		return errors.New("match is not started yet")
+	}
+	if room.match.Turn() != name {
+		if room.game.IsFinished() {
+			return game.ErrGameOver
+		}
+		return game.ErrNotYourTurn
+	}
+	var err error
+	switch c.Action {
+	case "open":
+		err = room.game.Open(c.Row, c.Col)
+	case "chord":
+		err = room.game.Chord(c.Row, c.Col)
+	default:
+		return fmt.Errorf("unknown versus action %q", c.Action)
+	}
+	if err != nil {
+		return err
+	}
+	room.broadcast()
+
+	return nil
+}
+
+// onMatchEvent sends match event to all players, the event is sent on behalf of the player who moved
+func (room *versusRoom) onMatchEvent(e game.Event) {
+	m := eventMessage(e)
+	if status := room.match.Status(); status.LastMove != nil {
+		m.Player = status.LastMove.Player
+	}
+	if e, ok := e.(versus.HoleFoundEvent); ok {
+		m.Player = e.Player
+	}
+	for _, watch := range room.recipients() {
+		watch.send(m)
+	}
+}
+
+// broadcast sends state of the match and the board to all players, room must be locked
+func (room *versusRoom) broadcast() {
+	messages := room.messages()
+	for _, watch := range room.recipients() {
+		for _, m := range messages {
+			watch.send(m)
+		}
+	}
+}
+
+// messages returns state of the match and the board when match is started, room must be locked
+func (room *versusRoom) messages() []Message {
+	state := room.lockedState()
+	messages := []Message{{Type: MessageVersus, Versus: &state}}
+	if room.match != nil {
+		board := newGameState(room.id, room.profile, room.game.GetState(), room.match.Snapshot(), room.game.Stats())
+		messages = append(messages, Message{Type: MessageState, State: &board})
+	}
+
+	return messages
+}
+
+// recipients returns connections of players and spectators, room must be locked
+func (room *versusRoom) recipients() []*watcher {
+	all := make([]*watcher, 0, len(room.watchers)+len(room.spectators))
+	for _, watch := range room.watchers {
+		all = append(all, watch)
+	}
+	for watch := range room.spectators {
+		all = append(all, watch)
+	}
+
+	return all
+}
+
+// spectate sends state of the match and then every event and change of it
+func (room *versusRoom) spectate(watch *watcher) (stop func()) {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	room.spectators[watch] = struct{}{}
+	for _, m := range room.messages() {
+		watch.send(m)
+	}
+
+	return func() {
+		room.mu.Lock()
+		defer room.mu.Unlock()
+		delete(room.spectators, watch)
+	}
+}
+
+func (room *versusRoom) info() SessionInfo {
+	state := room.state()
+	info := SessionInfo{Kind: kindVersus, Profile: room.profile, Players: state.Joined, State: "waiting"}
+	switch {
+	case state.Match == nil:
+	case state.Match.Finished:
+		info.State = "finished"
+	default:
+		info.State = "running"
+	}
+
+	return info
+}
+
+// handleVersuses creates new versus match: POST /api/versus
+func (s *Server) handleVersuses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+	var settings VersusSettings
+	err := decodeBody(w, r, &settings)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	delay, err := settings.spectateDelay()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	room, err := newVersusRoom(settings)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	room.id, err = newSessionID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	err = s.add(room.id, room, delay)
+	if errors.Is(err, errTooManySessions) {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, room.state())
+}
+
+// handleVersus serves existing versus match:
+//
+//	GET /api/versus/{id} returns state of the match
+//	GET /api/versus/{id}/live?player={name} joins the match over WebSocket, see handleVersusLive
+func (s *Server) handleVersus(w http.ResponseWriter, r *http.Request) {
+	parts, ok := pathParts(r, versusPrefix)
+	if !ok || len(parts) == 2 && parts[1] != "live" {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+	v, _ := s.get(parts[0])
+	room, ok := v.(*versusRoom)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("versus match is not found or expired"))
+		return
+	}
+	if len(parts) == 1 {
+		writeJSON(w, http.StatusOK, room.state())
+		return
+	}
+	s.handleVersusLive(w, r, room)
+}
+
+// handleVersusLive joins player to the match. players receive state of the match on every join,
+// and events of every move followed by state of the match and the board. commands are open and chord,
+// player who disconnects forfeits the match
+func (s *Server) handleVersusLive(w http.ResponseWriter, r *http.Request, room *versusRoom) {
+	name := r.URL.Query().Get("player")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, errors.New("player name is required"))
+		return
+	}
+
+	watch := newWatcher()
+	err := room.join(name, watch)
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	defer room.leave(name)
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// upgrader has already responded with error
+		return
+	}
+
+	s.serveLive(conn, room.id, watch, func(c Command) error {
+		return room.move(name, c)
+	})
+}
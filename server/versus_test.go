@@ -0,0 +1,136 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/proxx/game"
+	"github.com/proxx/versus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// joinVersus connects player to the versus match and reads state of the match sent on join
+func joinVersus(t *testing.T, srv *httptest.Server, id, player string) (*websocket.Conn, VersusState) {
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/versus/" + id + "/live?player=" + player
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	t.Cleanup(func() { conn.Close() })
+	m := readMessage(t, conn)
+	require.Equal(t, MessageVersus, m.Type)
+
+	return conn, *m.Versus
+}
+
+// readVersusMove reads messages of one move: attributed events, state of the match and the board
+func readVersusMove(t *testing.T, conn *websocket.Conn) ([]string, VersusState, *GameState) {
+	var events []string
+	for {
+		m := readMessage(t, conn)
+		if m.Type == MessageVersus {
+			_, state := readUntilState(t, conn)
+			return events, *m.Versus, state
+		}
+		events = append(events, m.Player+" "+string(m.Type))
+	}
+}
+
+func TestServer_Versus(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	var created VersusState
+	body := `{"code":"` + testCode(t) + `","players":2,"rule":"penalty"}`
+	require.Equal(t, http.StatusCreated, request(t, srv, http.MethodPost, "/api/versus", body, &created))
+	assert.Equal(t, 2, created.Players)
+	assert.Nil(t, created.Match)
+
+	alice, waiting := joinVersus(t, srv, created.ID, "alice")
+	assert.Equal(t, []string{"alice"}, waiting.Joined)
+	assert.Nil(t, waiting.Match)
+	require.NoError(t, alice.WriteJSON(Command{Action: "open", Row: 2, Col: 2}))
+	m := readMessage(t, alice)
+	assert.Equal(t, Message{Type: MessageError, Error: "match is not started yet"}, m)
+
+	// match starts when the last player joins
+	bob, started := joinVersus(t, srv, created.ID, "bob")
+	require.NotNil(t, started.Match)
+	assert.Equal(t, "alice", started.Match.Turn)
+	_, _, _ = readVersusMove(t, alice)
+	_, _ = readUntilState(t, bob)
+
+	require.NoError(t, bob.WriteJSON(Command{Action: "open", Row: 2, Col: 2}))
+	m = readMessage(t, bob)
+	assert.Equal(t, Message{Type: MessageError, Error: game.ErrNotYourTurn.Error()}, m)
+
+	// black hole costs alice points and stays flagged
+	require.NoError(t, alice.WriteJSON(Command{Action: "open", Row: 0, Col: 1}))
+	for _, conn := range []*websocket.Conn{alice, bob} {
+		events, state, board := readVersusMove(t, conn)
+		assert.Equal(t, []string{"alice holeFound"}, events)
+		assert.Equal(t, -versus.HolePenalty, state.Match.Players[0].Score)
+		assert.Equal(t, "bob", state.Match.Turn)
+		assert.Equal(t, []string{"#F#", "###", "###"}, board.Cells)
+	}
+
+	require.NoError(t, bob.WriteJSON(Command{Action: "open", Row: 2, Col: 2}))
+	events, _, _ := readVersusMove(t, alice)
+	assert.Len(t, events, 7)
+	assert.Equal(t, "bob cascadeFinished", events[6])
+	_, _, _ = readVersusMove(t, bob)
+
+	require.NoError(t, alice.WriteJSON(Command{Action: "chord", Row: 1, Col: 1}))
+	events, state, board := readVersusMove(t, bob)
+	assert.Equal(t, []string{"alice cellOpened", "alice cellOpened", "alice cascadeFinished", "alice gameWon"}, events)
+	assert.Equal(t, []string{"bob"}, state.Match.Winners)
+	assert.Equal(t, []versus.PlayerScore{
+		{Name: "alice", Score: -8, Cells: 2, HolesFound: 1},
+		{Name: "bob", Score: 6, Cells: 6},
+	}, state.Match.Players)
+	assert.Equal(t, []string{"1F1", "111", "000"}, board.Cells)
+
+	var got VersusState
+	require.Equal(t, http.StatusOK, request(t, srv, http.MethodGet, "/api/versus/"+created.ID, "", &got))
+	assert.Equal(t, state, got)
+
+	var errResp errorResponse
+	body = `{"preset":"beginner","players":2,"rule":"mercy"}`
+	require.Equal(t, http.StatusBadRequest, request(t, srv, http.MethodPost, "/api/versus", body, &errResp))
+	assert.Equal(t, `unknown rule "mercy", expected eliminate or penalty`, errResp.Error)
+}
+
+func TestServer_VersusForfeit(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	var created VersusState
+	body := `{"code":"` + testCode(t) + `","players":2}`
+	require.Equal(t, http.StatusCreated, request(t, srv, http.MethodPost, "/api/versus", body, &created))
+	alice, _ := joinVersus(t, srv, created.ID, "alice")
+	bob, _ := joinVersus(t, srv, created.ID, "bob")
+	_, _, _ = readVersusMove(t, alice)
+	_, _ = readUntilState(t, bob)
+
+	// the room is full
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/versus/" + created.ID + "/live?player=carol"
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	spectator := watch(t, srv, "")
+	_, watched, board := readVersusMove(t, spectator)
+	assertCantMove(t, srv, versusPrefix, watched.ID)
+	assertCantMove(t, srv, versusPrefix, board.ID)
+
+	// player who disconnects forfeits
+	require.NoError(t, alice.Close())
+	for _, conn := range []*websocket.Conn{bob, spectator} {
+		events, state, _ := readVersusMove(t, conn)
+		assert.Equal(t, []string{" gameWon"}, events)
+		assert.True(t, state.Match.Finished)
+		assert.Equal(t, []string{"bob"}, state.Match.Winners)
+	}
+}
@@ -0,0 +1,159 @@
+"use strict";
+
+// frontend of proxx serve. it plays the game over HTTP API, see server/handlers.go
+(function () {
+    const api = "api/games";
+    const symbols = {"#": "closed", "F": "flagged", "*": "hole"};
+
+    const form = document.getElementById("settings");
+    const preset = document.getElementById("preset");
+    const board = document.getElementById("board");
+    const status = document.getElementById("status");
+    const timer = document.getElementById("timer");
+    const undo = document.getElementById("undo");
+
+    let game = null;
+    let ticker = null;
+
+    // request sends action to the API and returns new state of the game. failed request shows error
+    async function request(method, path, body) {
+        const response = await fetch(path, {
+            method: method,
+            headers: {"Content-Type": "application/json"},
+            body: body === undefined ? undefined : JSON.stringify(body),
+        });
+        const data = await response.json();
+        if (!response.ok) {
+            throw new Error(data.error);
+        }
+        return data;
+    }
+
+    function settings() {
+        switch (preset.value) {
+            case "custom":
+                return {
+                    size: Number(document.getElementById("size").value),
+                    blackHoles: Number(document.getElementById("holes").value),
+                };
+            case "code":
+                return {code: document.getElementById("code").value.trim()};
+            default:
+                return {preset: preset.value};
+        }
+    }
+
+    async function newGame() {
+        try {
+            render(await request("POST", api, settings()));
+        } catch (e) {
+            status.textContent = e.message;
+        }
+    }
+
+    async function act(action, row, col) {
+        if (game === null) {
+            return;
+        }
+        const body = row === undefined ? undefined : {row: row, col: col};
+        try {
+            render(await request("POST", api + "/" + game.id + "/" + action, body));
+        } catch (e) {
+            status.textContent = e.message;
+        }
+    }
+
+    function render(state) {
+        game = state;
+        board.textContent = "";
+        state.cells.forEach(function (line, i) {
+            const tr = board.insertRow();
+            Array.from(line).forEach(function (symbol, j) {
+                const td = tr.insertCell();
+                td.dataset.row = String(state.top + i);
+                td.dataset.col = String(state.left + j);
+                if (symbol in symbols) {
+                    td.className = symbols[symbol];
+                    td.textContent = {"F": "⚑", "*": "●"}[symbol] || "";
+                } else {
+                    td.className = "opened v" + symbol;
+                    td.textContent = symbol === "0" ? "" : symbol;
+                }
+            });
+        });
+
+        document.getElementById("remaining").textContent = state.remainingHoles < 0 ? "∞" : state.remainingHoles;
+        document.getElementById("clicks").textContent = state.stats.clicks;
+        undo.disabled = state.stats.moves === 0;
+        switch (state.state) {
+            case "win":
+                status.textContent = "You win! Score " + state.stats.score;
+                break;
+            case "lose":
+                status.textContent = "You lose. Score " + state.stats.score;
+                break;
+            default:
+                status.textContent = "";
+        }
+        tick();
+        clearInterval(ticker);
+        if (state.state === "inProgress") {
+            ticker = setInterval(tick, 1000);
+        }
+    }
+
+    // tick shows game time, it is counted by browser while game is in progress
+    function tick() {
+        let seconds = game.stats.duration / 1e9;
+        if (game.state === "inProgress") {
+            seconds = (Date.now() - Date.parse(game.stats.startedAt)) / 1000;
+        }
+        timer.textContent = String(Math.max(0, Math.floor(seconds)));
+    }
+
+    function cellOf(event) {
+        const td = event.target.closest("td");
+        if (td === null) {
+            return null;
+        }
+        return {td: td, row: Number(td.dataset.row), col: Number(td.dataset.col)};
+    }
+
+    board.addEventListener("click", function (event) {
+        const cell = cellOf(event);
+        if (cell !== null && cell.td.classList.contains("closed")) {
+            act("open", cell.row, cell.col);
+        }
+    });
+
+    board.addEventListener("contextmenu", function (event) {
+        event.preventDefault();
+        const cell = cellOf(event);
+        if (cell === null) {
+            return;
+        }
+        if (cell.td.classList.contains("opened")) {
+            // only numbers are chorded
+            if (cell.td.textContent !== "") {
+                act("chord", cell.row, cell.col);
+            }
+        } else if (!cell.td.classList.contains("hole")) {
+            act("flag", cell.row, cell.col);
+        }
+    });
+
+    undo.addEventListener("click", function () {
+        act("undo");
+    });
+
+    preset.addEventListener("change", function () {
+        form.className = preset.value;
+    });
+
+    form.addEventListener("submit", function (event) {
+        event.preventDefault();
+        newGame();
+    });
+
+    newGame();
+})();
@@ -0,0 +1,294 @@
+// Package share packs boards and replays into short URL-safe codes players paste to each other
+package share
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/proxx/game"
+	"github.com/proxx/replay"
+)
+
+// format of the board code, base64url without padding:
+//
+//	header byte: version (high 4 bits) and kind (low 4 bits)
+//	seeded board: size, number of black holes, seed
+//	layout: rows, cols, number of black holes and distances between indexes (row*cols+col) of black holes
+//	infinite board: seed, density (8 bytes float64)
+//	3 bytes of CRC32 (IEEE) of everything above
+const (
+	version       = 1
+	checksumBytes = 3
+
+	kindSeeded   = 0
+	kindLayout   = 1
+	kindInfinite = 2
+
+	// replayPrefix starts replay codes, board codes never start with it as their first byte has version 1
+	replayPrefix = "r"
+)
+
+// MaxSide limits side of shared boards, so code can't make board nobody could play or one that takes all the memory
+const MaxSide = 200
+
+var (
+	// ErrInvalidCode is returned when code is mistyped or corrupted
+	ErrInvalidCode = errors.New("code is invalid, check it for typos")
+	// ErrUnsupportedVersion is returned for codes created by newer version of the game
+	ErrUnsupportedVersion = errors.New("code is created by unsupported version of the game")
+)
+
+// Board describes board shared by code. it is either board of fixed size generated from seed,
+// board with exact positions of black holes or infinite board
+type Board struct {
+	// Size, BlackHoles and Seed describe board generated by game.NewSeededBoard
+	Size, BlackHoles int
+	Seed             int64
+	// Layout is set for board with exact positions of black holes
+	Layout *game.Layout
+	// Infinite is set for infinite board, it is generated from Seed and Density
+	Infinite bool
+	Density  float64
+}
+
+// NewBoard creates the shared board
+func (b Board) NewBoard() (*game.Board, error) {
+	switch {
+	case b.Infinite:
+		return game.NewInfiniteBoard(b.Seed, b.Density)
+	case b.Layout != nil:
+		return game.NewBoardFromLayout(*b.Layout)
+	default:
+		return game.NewSeededBoard(b.Size, b.BlackHoles, b.Seed)
+	}
+}
+
+// Profile returns name results on the shared board are grouped by
+func (b Board) Profile() string {
+	switch {
+	case b.Infinite:
+		return game.InfiniteProfile
+	case b.Layout != nil:
+		return game.LayoutProfile(*b.Layout)
+	default:
+		return game.ProfileName(b.Size, b.BlackHoles)
+	}
+}
+
+// EncodeBoard packs board description into code, board must not be wider than MaxSide
+func EncodeBoard(b Board) (string, error) {
+	if b.Size > MaxSide || b.Layout != nil && (b.Layout.Rows > MaxSide || b.Layout.Cols > MaxSide) {
+		return "", fmt.Errorf("board side must not be bigger than %d", MaxSide)
+	}
+	var buf bytes.Buffer
+	switch {
+	case b.Infinite:
+		buf.WriteByte(version<<4 | kindInfinite)
+		putVarint(&buf, b.Seed)
+		var density [8]byte
+		binary.LittleEndian.PutUint64(density[:], math.Float64bits(b.Density))
+		buf.Write(density[:])
+	case b.Layout != nil:
+		buf.WriteByte(version<<4 | kindLayout)
+		l := b.Layout
+		putUvarint(&buf, uint64(l.Rows))
+		putUvarint(&buf, uint64(l.Cols))
+		putUvarint(&buf, uint64(len(l.BlackHoles)))
+		indexes := make([]int, 0, len(l.BlackHoles))
+		for _, h := range l.BlackHoles {
+			indexes = append(indexes, h[0]*l.Cols+h[1])
+		}
+		previous := -1
+		for _, index := range indexes {
+			if index <= previous {
+				return "", errors.New("black holes must be ordered row by row without duplicates")
+			}
+			putUvarint(&buf, uint64(index-previous-1))
+			previous = index
+		}
+	default:
+		if b.Size <= 0 || b.BlackHoles < 0 {
+			return "", fmt.Errorf("board size %d and number of black holes %d must not be negative", b.Size, b.BlackHoles)
+		}
+		buf.WriteByte(version<<4 | kindSeeded)
+		putUvarint(&buf, uint64(b.Size))
+		putUvarint(&buf, uint64(b.BlackHoles))
+		putVarint(&buf, b.Seed)
+	}
+
+	return encode(buf.Bytes()), nil
+}
+
+// DecodeBoard unpacks board description from code
+func DecodeBoard(code string) (Board, error) {
+	if strings.HasPrefix(code, replayPrefix) {
+		return Board{}, errors.New("it is replay code, not board code")
+	}
+	data, err := decode(code)
+	if err != nil {
+		return Board{}, err
+	}
+	if len(data) == 0 {
+		return Board{}, ErrInvalidCode
+	}
+	if data[0]>>4 != version {
+		return Board{}, fmt.Errorf("%w: version %d", ErrUnsupportedVersion, data[0]>>4)
+	}
+
+	r := bytes.NewReader(data[1:])
+	var b Board
+	switch data[0] & 0x0f {
+	case kindSeeded:
+		b.Size, err = readSide(r)
+		if err == nil {
+			b.BlackHoles, err = readCount(r, b.Size*b.Size)
+		}
+		if err == nil {
+			b.Seed, err = binary.ReadVarint(r)
+		}
+	case kindLayout:
+		b.Layout, err = readLayout(r)
+	case kindInfinite:
+		b.Infinite = true
+		b.Seed, err = binary.ReadVarint(r)
+		var density [8]byte
+		if err == nil {
+			_, err = io.ReadFull(r, density[:])
+		}
+		b.Density = math.Float64frombits(binary.LittleEndian.Uint64(density[:]))
+	default:
+		return Board{}, fmt.Errorf("%w: unknown board kind", ErrInvalidCode)
+	}
+	if err != nil || r.Len() != 0 {
+		return Board{}, ErrInvalidCode
+	}
+
+	return b, nil
+}
+
+// EncodeReplay packs recorded game into code
+func EncodeReplay(r *replay.Replay) (string, error) {
+	var buf bytes.Buffer
+	err := replay.Encode(&buf, r)
+	if err != nil {
+		return "", err
+	}
+
+	// replay format has its own version and checksum
+	return replayPrefix + base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// DecodeReplay unpacks recorded game from code
+func DecodeReplay(code string) (*replay.Replay, error) {
+	if !strings.HasPrefix(code, replayPrefix) {
+		return nil, errors.New("it is not replay code")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(code, replayPrefix))
+	if err != nil {
+		return nil, ErrInvalidCode
+	}
+	r, err := replay.Decode(bytes.NewReader(data))
+	if errors.Is(err, replay.ErrCorrupted) {
+		return nil, ErrInvalidCode
+	}
+
+	return r, err
+}
+
+// encode appends checksum to data and converts it to text
+func encode(data []byte) string {
+	var checksum [4]byte
+	binary.BigEndian.PutUint32(checksum[:], crc32.ChecksumIEEE(data))
+
+	return base64.RawURLEncoding.EncodeToString(append(data, checksum[:checksumBytes]...))
+}
+
+// decode converts text to data checking its checksum
+func decode(code string) ([]byte, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(code))
+	if err != nil || len(data) <= checksumBytes {
+		return nil, ErrInvalidCode
+	}
+	data, checksum := data[:len(data)-checksumBytes], data[len(data)-checksumBytes:]
+	var expected [4]byte
+	binary.BigEndian.PutUint32(expected[:], crc32.ChecksumIEEE(data))
+	if !bytes.Equal(checksum, expected[:checksumBytes]) {
+		return nil, ErrInvalidCode
+	}
+
+	return data, nil
+}
+
+func readLayout(r *bytes.Reader) (*game.Layout, error) {
+	rows, err := readSide(r)
+	if err != nil {
+		return nil, err
+	}
+	cols, err := readSide(r)
+	if err != nil {
+		return nil, err
+	}
+	holes, err := readCount(r, rows*cols)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &game.Layout{Rows: rows, Cols: cols, BlackHoles: make([][]int, 0, holes)}
+	index := -1
+	for i := 0; i < holes; i++ {
+		distance, err := readCount(r, rows*cols)
+		if err != nil {
+			return nil, err
+		}
+		index += distance + 1
+		if index >= rows*cols {
+			return nil, ErrInvalidCode
+		}
+		l.BlackHoles = append(l.BlackHoles, []int{index / cols, index % cols})
+	}
+
+	return l, nil
+}
+
+// readCount reads number checking it is not bigger than limit
+func readCount(r *bytes.Reader, limit int) (int, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(limit) {
+		return 0, ErrInvalidCode
+	}
+
+	return int(n), nil
+}
+
+// readSide reads side of the board, it must not be bigger than MaxSide
+func readSide(r *bytes.Reader) (int, error) {
+	side, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, err
+	}
+	if side > MaxSide {
+		return 0, fmt.Errorf("%w: board side must not be bigger than %d", ErrInvalidCode, MaxSide)
+	}
+
+	return int(side), nil
+}
+
+func putUvarint(buf *bytes.Buffer, v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+func putVarint(buf *bytes.Buffer, v int64) {
+	var b [binary.MaxVarintLen64]byte
+	buf.Write(b[:binary.PutVarint(b[:], v)])
+}
@@ -0,0 +1,128 @@
+package share
+
+import (
+	"testing"
+	"time"
+
+	"github.com/proxx/game"
+	"github.com/proxx/replay"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecodeBoard(t *testing.T) {
+	tests := []struct {
+		name        string
+		board       Board
+		wantProfile string
+	}{
+		{
+			name:        "seeded",
+			board:       Board{Size: 16, BlackHoles: 40, Seed: -1234567890},
+			wantProfile: "intermediate",
+		},
+		{
+			name: "layout",
+			board: Board{Layout: &game.Layout{Rows: 3, Cols: 4, BlackHoles: [][]int{
+				{0, 0}, {0, 1}, {2, 3},
+			}}},
+			wantProfile: "custom-3x4-3",
+		},
+		{
+			name:        "infinite",
+			board:       Board{Infinite: true, Seed: 42, Density: 0.15},
+			wantProfile: game.InfiniteProfile,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := EncodeBoard(tt.board)
+			require.NoError(t, err)
+			assert.NotContains(t, code, "=")
+
+			got, err := DecodeBoard(code)
+			require.NoError(t, err)
+			assert.Equal(t, tt.board, got)
+			assert.Equal(t, tt.wantProfile, got.Profile())
+		})
+	}
+}
+
+func TestEncodeBoard_TooBig(t *testing.T) {
+	_, err := EncodeBoard(Board{Size: MaxSide + 1, BlackHoles: 10})
+	assert.EqualError(t, err, "board side must not be bigger than 200")
+	_, err = EncodeBoard(Board{Layout: &game.Layout{Rows: 1, Cols: MaxSide + 1}})
+	assert.EqualError(t, err, "board side must not be bigger than 200")
+}
+
+func TestDecodeBoard_SameBoard(t *testing.T) {
+	original, err := game.NewSeededBoard(9, 10, 99)
+	require.NoError(t, err)
+	layout, err := original.Layout()
+	require.NoError(t, err)
+
+	for _, b := range []Board{{Size: 9, BlackHoles: 10, Seed: 99}, {Layout: &layout}} {
+		code, err := EncodeBoard(b)
+		require.NoError(t, err)
+		decoded, err := DecodeBoard(code)
+		require.NoError(t, err)
+		shared, err := decoded.NewBoard()
+		require.NoError(t, err)
+
+		got, err := shared.Layout()
+		require.NoError(t, err)
+		assert.Equal(t, layout, got)
+	}
+}
+
+func TestDecodeBoard_Invalid(t *testing.T) {
+	code, err := EncodeBoard(Board{Size: 9, BlackHoles: 10, Seed: 99})
+	require.NoError(t, err)
+	typo := []byte(code)
+	typo[2]++
+
+	newer := encode([]byte{2<<4 | kindSeeded, 9, 10, 0})
+
+	tests := []struct {
+		name    string
+		code    string
+		wantErr error
+	}{
+		{name: "typo", code: string(typo), wantErr: ErrInvalidCode},
+		{name: "truncated", code: code[:len(code)-1], wantErr: ErrInvalidCode},
+		{name: "not_base64", code: "hello world", wantErr: ErrInvalidCode},
+		{name: "empty", code: "", wantErr: ErrInvalidCode},
+		{name: "newer_version", code: newer, wantErr: ErrUnsupportedVersion},
+		{name: "too_many_holes", code: encode([]byte{version<<4 | kindSeeded, 2, 5, 0}), wantErr: ErrInvalidCode},
+		// 8192x8192 board is bigger than MaxSide
+		{name: "too_big", code: encode([]byte{version<<4 | kindSeeded, 0x80, 0x40, 1, 0}), wantErr: ErrInvalidCode},
+		{name: "too_wide", code: encode([]byte{version<<4 | kindLayout, 1, MaxSide + 1, 0}), wantErr: ErrInvalidCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeBoard(tt.code)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestEncodeDecodeReplay(t *testing.T) {
+	r := &replay.Replay{
+		Layout:    &game.Layout{Rows: 2, Cols: 2, BlackHoles: [][]int{{1, 1}}},
+		StartedAt: time.UnixMilli(1609495200000),
+		Actions: []replay.Action{
+			{Kind: replay.ActionOpen, At: time.Second, Outcome: game.OutcomeSafe, Opened: 1, State: game.BoardInProgress},
+		},
+	}
+	code, err := EncodeReplay(r)
+	require.NoError(t, err)
+
+	got, err := DecodeReplay(code)
+	require.NoError(t, err)
+	assert.Equal(t, r, got)
+
+	_, err = DecodeBoard(code)
+	assert.EqualError(t, err, "it is replay code, not board code")
+	_, err = DecodeReplay(code[:len(code)-2])
+	assert.ErrorIs(t, err, ErrInvalidCode)
+}
@@ -0,0 +1,386 @@
+// Package versus runs turn-based games where players take turns on one board and score points for opened cells
+package versus
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/proxx/game"
+)
+
+// scoring rules
+const (
+	// PointsPerCell is score of every cell opened by the player, cascades included
+	PointsPerCell = 1
+	// HolePenalty is taken from player who opens black hole by Penalty rule
+	HolePenalty = 10
+
+	// MaxPlayers limits number of players in the match
+	MaxPlayers = 8
+)
+
+var (
+	// ErrNotAllowed is returned on flags, undos and hints which are not a part of the match
+	ErrNotAllowed = errors.New("only opens and chords are allowed in versus match")
+)
+
+// EventHoleFound is kind of HoleFoundEvent
+const EventHoleFound game.EventKind = "holeFound"
+
+// HoleFoundEvent is sent when player opens black hole. black hole is flagged and match goes on
+type HoleFoundEvent struct {
+	Player     string
+	Row, Col   int
+	Eliminated bool
+}
+
+// Kind implements game.Event interface
+func (HoleFoundEvent) Kind() game.EventKind { return EventHoleFound }
+
+// Rule is what happens to the player who opens black hole
+type Rule string
+
+// list of rules
+const (
+	// Eliminate takes player out of the match, the last player left wins
+	Eliminate Rule = "eliminate"
+	// Penalty takes HolePenalty points from the player
+	Penalty Rule = "penalty"
+)
+
+// ParseRule parses rule name, empty name is Eliminate
+func ParseRule(name string) (Rule, error) {
+	switch Rule(name) {
+	case "", Eliminate:
+		return Eliminate, nil
+	case Penalty:
+		return Penalty, nil
+	default:
+		return "", fmt.Errorf("unknown rule %q, expected %s or %s", name, Eliminate, Penalty)
+	}
+}
+
+// PlayerScore is result of the player
+type PlayerScore struct {
+	Name       string `json:"name"`
+	Score      int    `json:"score"`
+	Cells      int    `json:"cells"`
+	HolesFound int    `json:"holesFound"`
+	Eliminated bool   `json:"eliminated,omitempty"`
+}
+
+// Move is the last move of the match
+type Move struct {
+	Player string `json:"player"`
+	Row    int    `json:"row"`
+	Col    int    `json:"col"`
+	// Points is change of the player's score
+	Points int  `json:"points"`
+	Hole   bool `json:"hole,omitempty"`
+}
+
+// Status is state of the match
+type Status struct {
+	Rule Rule `json:"rule"`
+	// Turn is name of the player who moves next, it is empty when match is finished
+	Turn     string `json:"turn,omitempty"`
+	Finished bool   `json:"finished"`
+	// Winners are players with the best score, or the last player left by Eliminate rule
+	Winners  []string      `json:"winners,omitempty"`
+	Players  []PlayerScore `json:"players"`
+	LastMove *Move         `json:"lastMove,omitempty"`
+}
+
+// Match is a game of several players taking turns on one board. it implements game.Playground,
+// so it is played by game.Game: every click is made by the player whose turn it is
+type Match struct {
+	game.View
+	mu       sync.Mutex
+	board    *game.Board
+	rule     Rule
+	players  []*PlayerScore
+	turn     int
+	finished bool
+	lastMove *Move
+
+	events game.Notifier
+}
+
+// New creates match of players on the board. players take turns in the given order
+func New(b *game.Board, rule Rule, players ...string) (*Match, error) {
+	if rule != Eliminate && rule != Penalty {
+		return nil, fmt.Errorf("unknown rule %q", rule)
+	}
+	if len(players) < 2 || len(players) > MaxPlayers {
+		return nil, fmt.Errorf("number of players must be from 2 to %d", MaxPlayers)
+	}
+	if rows, _ := b.Dimensions(); rows == 0 {
+		return nil, errors.New("infinite board can't be cleared, so match on it never ends")
+	}
+	m := &Match{View: b, board: b, rule: rule}
+	names := make(map[string]bool, len(players))
+	for _, name := range players {
+		if name == "" || names[name] {
+			return nil, fmt.Errorf("player name %q is empty or taken", name)
+		}
+		names[name] = true
+		m.players = append(m.players, &PlayerScore{Name: name})
+	}
+
+	return m, nil
+}
+
+// Subscribe registers fn to be called on match events: opened cells, found black holes and the end of the match.
+// events are delivered after the move is finished. returned function unregisters fn
+func (m *Match) Subscribe(fn func(game.Event)) (unsubscribe func()) {
+	return m.events.Subscribe(func(e interface{}) { fn(e.(game.Event)) })
+}
+
+// Turn returns name of the player who moves next, it is empty when match is finished
+func (m *Match) Turn() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.turnName()
+}
+
+func (m *Match) turnName() string {
+	if m.finished {
+		return ""
+	}
+
+	return m.players[m.turn].Name
+}
+
+// Status returns state of the match
+func (m *Match) Status() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := Status{Rule: m.rule, Turn: m.turnName(), Finished: m.finished, LastMove: m.lastMove}
+	for _, p := range m.players {
+		s.Players = append(s.Players, *p)
+	}
+	if m.finished {
+		s.Winners = m.winners()
+	}
+
+	return s
+}
+
+// Click opens the cell for the player whose turn it is
+func (m *Match) Click(click []int) error {
+	return m.move(click, func(click []int) (game.ClickResult, error) {
+		result, err := m.board.Open(click)
+		if err == nil && result.Outcome == game.OutcomeAlreadyOpen {
+			return result, game.ErrCellOpened
+		}
+
+		return result, err
+	})
+}
+
+// Chord opens neighbors of the numbered cell for the player whose turn it is
+func (m *Match) Chord(click []int) (game.ClickResult, error) {
+	var result game.ClickResult
+	err := m.move(click, func(click []int) (game.ClickResult, error) {
+		var err error
+		result, err = m.board.Chord(click)
+		if err == nil && result.Outcome == game.OutcomeAlreadyOpen {
+			return result, errors.New("there are no cells to open around")
+		}
+
+		return result, err
+	})
+
+	return result, err
+}
+
+// Forfeit takes player out of the match, e.g. when player disconnects
+func (m *Match) Forfeit(name string) {
+	defer m.flush()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.finished {
+		return
+	}
+	for i, p := range m.players {
+		if p.Name != name || p.Eliminated {
+			continue
+		}
+		p.Eliminated = true
+		if i == m.turn {
+			m.pass()
+		}
+		m.checkFinished(false)
+		return
+	}
+}
+
+// move makes move of the current player and passes the turn
+func (m *Match) move(click []int, open func(click []int) (game.ClickResult, error)) error {
+	defer m.flush()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.finished {
+		return game.ErrGameOver
+	}
+	p := m.players[m.turn]
+	result, err := open(click)
+	if err != nil {
+		return err
+	}
+
+	move := &Move{Player: p.Name, Row: click[0], Col: click[1]}
+	if result.Outcome == game.OutcomeHole {
+		hole := result.Opened[0]
+		err = m.coverHole(hole.Row, hole.Col)
+		if err != nil {
+			return err
+		}
+		p.HolesFound++
+		move.Hole = true
+		if m.rule == Penalty {
+			move.Points = -HolePenalty
+		} else {
+			p.Eliminated = true
+		}
+		m.events.Queue(HoleFoundEvent{Player: p.Name, Row: hole.Row, Col: hole.Col, Eliminated: p.Eliminated})
+	} else {
+		p.Cells += len(result.Opened)
+		move.Points = len(result.Opened) * PointsPerCell
+		for _, c := range result.Opened {
+			m.events.Queue(game.CellOpenedEvent(c))
+		}
+		if result.Outcome == game.OutcomeCascade {
+			m.events.Queue(game.CascadeFinishedEvent{Row: click[0], Col: click[1], Opened: len(result.Opened)})
+		}
+	}
+	p.Score += move.Points
+	m.lastMove = move
+	m.pass()
+	m.checkFinished(result.State == game.BoardCleared)
+
+	return nil
+}
+
+// coverHole reverts the move which opened black hole and flags it, so the board could be played further
+func (m *Match) coverHole(row, col int) error {
+	err := m.board.Undo()
+	if err != nil {
+		return err
+	}
+	_, err = m.board.ToggleFlag([]int{row, col})
+
+	return err
+}
+
+// pass gives the turn to the next player who is not eliminated
+func (m *Match) pass() {
+	for i := 1; i <= len(m.players); i++ {
+		next := (m.turn + i) % len(m.players)
+		if !m.players[next].Eliminated {
+			m.turn = next
+			return
+		}
+	}
+}
+
+// checkFinished finishes the match when board is cleared or by Eliminate rule only one player is left
+func (m *Match) checkFinished(cleared bool) {
+	active := 0
+	for _, p := range m.players {
+		if !p.Eliminated {
+			active++
+		}
+	}
+	if !cleared && active > 1 {
+		return
+	}
+	m.finished = true
+	m.events.Queue(game.GameWonEvent{Revealed: m.board.Score()})
+}
+
+// winners returns players with the best score among players who are not eliminated
+func (m *Match) winners() []string {
+	best := 0
+	var winners []string
+	for _, p := range m.players {
+		switch {
+		case p.Eliminated:
+		case len(winners) == 0 || p.Score > best:
+			best, winners = p.Score, []string{p.Name}
+		case p.Score == best:
+			winners = append(winners, p.Name)
+		}
+	}
+
+	return winners
+}
+
+// flush delivers pending events to subscribers
+func (m *Match) flush() {
+	m.events.Flush()
+}
+
+// ToggleFlag is not allowed, flags are put only on found black holes
+func (m *Match) ToggleFlag([]int) (bool, error) {
+	return false, ErrNotAllowed
+}
+
+// Undo is not allowed, moves of the match are final
+func (m *Match) Undo() error {
+	return ErrNotAllowed
+}
+
+// Hint is not allowed, nobody would open cell for the opponent
+func (m *Match) Hint() (row, col int, err error) {
+	return 0, 0, ErrNotAllowed
+}
+
+// WinState reports whether the match is finished
+func (m *Match) WinState() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.finished
+}
+
+// LoseState is always false, the match is won by somebody
+func (m *Match) LoseState() bool {
+	return false
+}
+
+// Prompt lists commands allowed in the match: only opens and chords
+func (m *Match) Prompt() string {
+	return "Enter board coordinates - row and column (two digits with space) to open, " +
+		"or ch with coordinates to chord:"
+}
+
+// Print prints the board, scores and whose turn it is
+func (m *Match) Print() {
+	m.board.Print()
+	s := m.Status()
+	var line strings.Builder
+	for i, p := range s.Players {
+		if i > 0 {
+			line.WriteString(", ")
+		}
+		fmt.Fprintf(&line, "%s: %d", p.Name, p.Score)
+		if p.Eliminated {
+			line.WriteString(" (out)")
+		}
+	}
+	fmt.Fprintln(os.Stdout, "Score:", line.String())
+	switch {
+	case s.Finished:
+		fmt.Fprintf(os.Stdout, "Match is over, winner: %s\n", strings.Join(s.Winners, ", "))
+	default:
+		fmt.Fprintf(os.Stdout, "%s's turn\n", s.Turn)
+	}
+}
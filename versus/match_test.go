@@ -0,0 +1,194 @@
+package versus
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/proxx/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMatch creates match on 3x3 board with black hole at [0 1]
+func newTestMatch(t *testing.T, rule Rule, players ...string) *Match {
+	b, err := game.NewBoardFromLayout(game.Layout{Rows: 3, Cols: 3, BlackHoles: [][]int{{0, 1}}})
+	require.NoError(t, err)
+	m, err := New(b, rule, players...)
+	require.NoError(t, err)
+
+	return m
+}
+
+func TestNew(t *testing.T) {
+	b, err := game.NewBoardFromLayout(game.Layout{Rows: 3, Cols: 3, BlackHoles: [][]int{{0, 1}}})
+	require.NoError(t, err)
+	infinite, err := game.NewInfiniteBoard(1, 0.15)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		board   *game.Board
+		rule    Rule
+		players []string
+		err     string
+	}{
+		{name: "single player", board: b, rule: Eliminate, players: []string{"alice"},
+			err: "number of players must be from 2 to 8"},
+		{name: "same names", board: b, rule: Penalty, players: []string{"alice", "alice"},
+			err: `player name "alice" is empty or taken`},
+		{name: "unknown rule", board: b, rule: "mercy", players: []string{"alice", "bob"}, err: `unknown rule "mercy"`},
+		{name: "infinite board", board: infinite, rule: Eliminate, players: []string{"alice", "bob"},
+			err: "infinite board can't be cleared, so match on it never ends"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.board, tt.rule, tt.players...)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestMatch_Eliminate(t *testing.T) {
+	m := newTestMatch(t, Eliminate, "alice", "bob", "carol")
+	var events []string
+	m.Subscribe(func(e game.Event) {
+		events = append(events, string(e.Kind()))
+	})
+
+	require.NoError(t, m.Click([]int{2, 2}))
+	assert.Equal(t, "bob", m.Turn())
+	// black hole eliminates bob and is flagged for the others
+	require.NoError(t, m.Click([]int{0, 1}))
+	assert.Equal(t, "carol", m.Turn())
+	cell, err := m.Cell(0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, game.CellFlagged, cell.State)
+	assert.Equal(t, &Move{Player: "bob", Row: 0, Col: 1, Hole: true}, m.Status().LastMove)
+	// failed move doesn't pass the turn
+	assert.ErrorIs(t, m.Click([]int{2, 2}), game.ErrCellOpened)
+	require.NoError(t, m.Click([]int{0, 0}))
+	// eliminated player is skipped
+	assert.Equal(t, "alice", m.Turn())
+	require.NoError(t, m.Click([]int{0, 2}))
+	assert.ErrorIs(t, m.Click([]int{0, 0}), game.ErrGameOver)
+
+	assert.Equal(t, Status{
+		Rule:     Eliminate,
+		Finished: true,
+		Winners:  []string{"alice"},
+		Players: []PlayerScore{
+			{Name: "alice", Score: 7, Cells: 7},
+			{Name: "bob", HolesFound: 1, Eliminated: true},
+			{Name: "carol", Score: 1, Cells: 1},
+		},
+		LastMove: &Move{Player: "alice", Row: 0, Col: 2, Points: 1},
+	}, m.Status())
+	assert.True(t, m.WinState())
+	assert.Equal(t, []string{
+		"cellOpened", "cellOpened", "cellOpened", "cellOpened", "cellOpened", "cellOpened", "cascadeFinished",
+		"holeFound",
+		"cellOpened",
+		"cellOpened", "gameWon",
+	}, events)
+}
+
+func TestMatch_Penalty(t *testing.T) {
+	m := newTestMatch(t, Penalty, "alice", "bob")
+
+	require.NoError(t, m.Click([]int{0, 1}))
+	require.NoError(t, m.Click([]int{2, 2}))
+	// chord opens the cells around flagged black hole
+	_, err := m.Chord([]int{1, 1})
+	require.NoError(t, err)
+
+	assert.Equal(t, Status{
+		Rule:     Penalty,
+		Finished: true,
+		Winners:  []string{"bob"},
+		Players: []PlayerScore{
+			{Name: "alice", Score: -8, Cells: 2, HolesFound: 1},
+			{Name: "bob", Score: 6, Cells: 6},
+		},
+		LastMove: &Move{Player: "alice", Row: 1, Col: 1, Points: 2},
+	}, m.Status())
+}
+
+func TestMatch_Forfeit(t *testing.T) {
+	m := newTestMatch(t, Penalty, "alice", "bob", "carol")
+
+	m.Forfeit("alice")
+	assert.Equal(t, "bob", m.Turn())
+	require.NoError(t, m.Click([]int{2, 2}))
+	m.Forfeit("carol")
+
+	status := m.Status()
+	assert.True(t, status.Finished)
+	assert.Equal(t, []string{"bob"}, status.Winners)
+}
+
+func TestMatch_NotAllowed(t *testing.T) {
+	m := newTestMatch(t, Eliminate, "alice", "bob")
+
+	_, err := m.ToggleFlag([]int{0, 1})
+	assert.ErrorIs(t, err, ErrNotAllowed)
+	assert.ErrorIs(t, m.Undo(), ErrNotAllowed)
+	_, _, err = m.Hint()
+	assert.ErrorIs(t, err, ErrNotAllowed)
+	_, err = m.Chord([]int{0, 0})
+	assert.Error(t, err)
+	assert.Equal(t, "alice", m.Turn())
+
+	// prompt lists only allowed commands
+	assert.Contains(t, m.Prompt(), "ch with coordinates to chord")
+	for _, rejected := range []string{"flag", "undo", "hint"} {
+		assert.NotContains(t, m.Prompt(), rejected)
+	}
+}
+
+// captureStdout returns everything fn prints
+func captureStdout(t *testing.T, fn func()) string {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	printed := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		printed <- string(data)
+	}()
+	fn()
+	require.NoError(t, w.Close())
+
+	return <-printed
+}
+
+func TestMatch_Run(t *testing.T) {
+	m := newTestMatch(t, Eliminate, "alice", "bob")
+	g := game.NewGame(m)
+
+	// hot-seat players type moves in turns at one terminal
+	moves := []string{"3 3", "f 1 2", "1 1", "1 2"}
+	var err error
+	printed := captureStdout(t, func() {
+		err = g.Run(context.Background(), strings.NewReader(strings.Join(moves, "\n")+"\n"))
+	})
+	require.NoError(t, err)
+	assert.Contains(t, printed, "Match is over, winner: bob")
+	// result and summary of a single player are not printed
+	assert.NotContains(t, printed, "You win")
+	assert.NotContains(t, printed, "Time:")
+	assert.NotContains(t, printed, "u to undo")
+
+	// alice opens black hole and bob is the last player left
+	assert.True(t, g.Won())
+	assert.Equal(t, []string{"bob"}, m.Status().Winners)
+	assert.Equal(t, []PlayerScore{
+		{Name: "alice", Score: 6, Cells: 6, HolesFound: 1, Eliminated: true},
+		{Name: "bob", Score: 1, Cells: 1},
+	}, m.Status().Players)
+}